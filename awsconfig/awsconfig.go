@@ -0,0 +1,190 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package awsconfig reads the shared configuration and credentials files
+// used by AWS tools, conventionally ~/.aws/config and ~/.aws/credentials.
+//
+// In the config file, the section for a profile is named "profile NAME",
+// except for the default profile, which may be named "default". Sections for
+// SSO sessions and service endpoints are named "sso-session NAME" and
+// "services NAME". In the credentials file, each section is named for a
+// profile with no prefix.
+//
+// A setting may have a nested block of settings, written as a key with no
+// value followed by indented "key = value" lines:
+//
+//	[profile dev]
+//	s3 =
+//	  max_concurrent_requests = 20
+//	  addressing_style = path
+package awsconfig
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/creachadair/ini"
+)
+
+// Dialect is the INI dialect of AWS configuration files. Comments begin with
+// "#" or ";", and indented lines following a key are kept with its value.
+var Dialect = ini.Dialect{HashComments: true, MultilineValues: true}
+
+// Types of sections.
+const (
+	TypeProfile    = "profile"
+	TypeSSOSession = "sso-session"
+	TypeServices   = "services"
+)
+
+// A Section is a section of an AWS configuration file.
+type Section struct {
+	Type     string            // the type of section, e.g., TypeProfile
+	Name     string            // the name of the section, without its type
+	Settings map[string]string // top-level settings
+
+	// Nested holds the nested blocks of settings, keyed by the name of the
+	// setting that contains them.
+	Nested map[string]map[string]string
+}
+
+// Get returns the value of the named top-level setting, or "".
+func (s *Section) Get(key string) string { return s.Settings[key] }
+
+// GetNested returns the value of the named setting in the named nested block,
+// or "".
+func (s *Section) GetNested(key, sub string) string { return s.Nested[key][sub] }
+
+// A File is the parsed contents of an AWS configuration or credentials file.
+type File struct {
+	Sections []*Section // in order of first occurrence
+}
+
+// ReadConfig parses an AWS config file from r. Sections whose names do not
+// have a recognized form are ignored.
+func ReadConfig(r io.Reader) (*File, error) {
+	return read(r, func(name string) (typ, rest string) {
+		if name == "default" {
+			return TypeProfile, name
+		}
+		typ, rest, ok := strings.Cut(name, " ")
+		switch typ {
+		case TypeProfile, TypeSSOSession, TypeServices:
+			if ok {
+				return typ, strings.TrimSpace(rest)
+			}
+		}
+		return "", ""
+	})
+}
+
+// ReadCredentials parses an AWS credentials file from r. Every section is a
+// profile named by its header.
+func ReadCredentials(r io.Reader) (*File, error) {
+	return read(r, func(name string) (string, string) { return TypeProfile, name })
+}
+
+// Profile returns the profile with the given name, or nil if there is none.
+func (f *File) Profile(name string) *Section { return f.find(TypeProfile, name) }
+
+// SSOSession returns the SSO session with the given name, or nil if there is
+// none.
+func (f *File) SSOSession(name string) *Section { return f.find(TypeSSOSession, name) }
+
+// Profiles returns the names of the profiles in f, in order of occurrence.
+func (f *File) Profiles() []string {
+	var out []string
+	for _, s := range f.Sections {
+		if s.Type == TypeProfile {
+			out = append(out, s.Name)
+		}
+	}
+	return out
+}
+
+func (f *File) find(typ, name string) *Section {
+	for _, s := range f.Sections {
+		if s.Type == typ && s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// read parses an AWS file from r, using split to recover the type and name
+// of each section from its header. A section with an empty type is skipped.
+// A section that occurs more than once is merged into its first occurrence.
+func read(r io.Reader, split func(string) (typ, name string)) (*File, error) {
+	f := new(File)
+	var cur *Section
+	err := ini.Parse(r, ini.Handler{
+		Dialect: Dialect,
+		Section: func(loc ini.Location, name string) error {
+			typ, rest := split(name)
+			if typ == "" {
+				cur = nil
+			} else if cur = f.find(typ, rest); cur == nil {
+				cur = &Section{Type: typ, Name: rest, Settings: make(map[string]string)}
+				f.Sections = append(f.Sections, cur)
+			}
+			return nil
+		},
+		KeyValue: func(loc ini.Location, key string, values []string) error {
+			if cur == nil {
+				return nil // outside any recognized section
+			}
+			value := ini.JoinValues(values, ini.JoinLast)
+			block, ok := strings.CutPrefix(value, "\n")
+			if !ok {
+				cur.Settings[key] = value
+				return nil
+			}
+			nested, err := parseNested(loc, block)
+			if err != nil {
+				return err
+			}
+			if cur.Nested == nil {
+				cur.Nested = make(map[string]map[string]string)
+			}
+			cur.Nested[key] = nested
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// parseNested parses the lines of a nested block of settings for the key at
+// loc.
+func parseNested(loc ini.Location, block string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, line := range strings.Split(block, "\n") {
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, &ini.SyntaxError{
+				Location: loc,
+				Desc:     "invalid nested setting",
+				Key:      fmt.Sprintf("%q", line),
+			}
+		}
+		out[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return out, nil
+}
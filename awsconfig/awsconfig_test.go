@@ -0,0 +1,111 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsconfig_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini/awsconfig"
+	"github.com/google/go-cmp/cmp"
+)
+
+const configFile = `[default]
+region = us-east-1
+output = json
+
+# Development account.
+[profile dev]
+region = us-west-2
+credential_process = /bin/creds --mode=dev
+s3 =
+  max_concurrent_requests = 20
+  ; comments are allowed
+  addressing_style = path
+sso_session = main
+
+[sso-session main]
+sso_region = us-east-1
+
+[unrecognized]
+ignored = true
+
+[profile dev]
+output = text
+`
+
+func TestReadConfig(t *testing.T) {
+	f, err := awsconfig.ReadConfig(strings.NewReader(configFile))
+	if err != nil {
+		t.Fatalf("ReadConfig failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"default", "dev"}, f.Profiles()); diff != "" {
+		t.Errorf("Profiles (-want, +got)\n%s", diff)
+	}
+
+	want := &awsconfig.Section{
+		Type: awsconfig.TypeProfile,
+		Name: "dev",
+		Settings: map[string]string{
+			"region":             "us-west-2",
+			"credential_process": "/bin/creds --mode=dev",
+			"sso_session":        "main",
+			"output":             "text",
+		},
+		Nested: map[string]map[string]string{
+			"s3": {"max_concurrent_requests": "20", "addressing_style": "path"},
+		},
+	}
+	dev := f.Profile("dev")
+	if diff := cmp.Diff(want, dev); diff != "" {
+		t.Errorf("Profile(dev) (-want, +got)\n%s", diff)
+	}
+	if got := dev.GetNested("s3", "addressing_style"); got != "path" {
+		t.Errorf("GetNested(s3, addressing_style): got %q, want path", got)
+	}
+	if got := f.Profile("default").Get("region"); got != "us-east-1" {
+		t.Errorf("Profile(default) region: got %q, want us-east-1", got)
+	}
+	if got := f.SSOSession("main").Get("sso_region"); got != "us-east-1" {
+		t.Errorf("SSOSession(main) sso_region: got %q, want us-east-1", got)
+	}
+	if s := f.Profile("unrecognized"); s != nil {
+		t.Errorf("Profile(unrecognized): got %+v, want nil", s)
+	}
+}
+
+func TestReadCredentials(t *testing.T) {
+	f, err := awsconfig.ReadCredentials(strings.NewReader(`[default]
+aws_access_key_id = AKIDEXAMPLE
+[ci]
+aws_access_key_id = AKIDCI
+`))
+	if err != nil {
+		t.Fatalf("ReadCredentials failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"default", "ci"}, f.Profiles()); diff != "" {
+		t.Errorf("Profiles (-want, +got)\n%s", diff)
+	}
+	if got := f.Profile("ci").Get("aws_access_key_id"); got != "AKIDCI" {
+		t.Errorf("Profile(ci) key: got %q, want AKIDCI", got)
+	}
+}
+
+func TestNestedError(t *testing.T) {
+	_, err := awsconfig.ReadConfig(strings.NewReader("[default]\ns3 =\n  bogus\n"))
+	if err == nil {
+		t.Error("ReadConfig: got nil, want error")
+	}
+}
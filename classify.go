@@ -0,0 +1,49 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import "io"
+
+// A LineInfo describes a physical line of INI input.
+type LineInfo struct {
+	Line int    // the line number, 1-based
+	File string // the included file containing the line (or "")
+	Kind string // the classification of the line; one of the Line constants
+	Text string // the raw text of the line, without its line terminator
+}
+
+// Classifications of lines.
+const (
+	LineBlank        = "blank"        // empty or only whitespace
+	LineComment      = "comment"      // a comment
+	LineSection      = "section"      // a section header, or the start or end of a block
+	LineKey          = "key"          // a key, with or without a value
+	LineContinuation = "continuation" // an additional value for the preceding key
+)
+
+// Classify scans the INI data from r and calls f with the classification of
+// each physical line, in order. If f reports an error, scanning stops and
+// that error is returned. Classify reports a syntax error as Parse does,
+// after classifying the lines before the error. It uses the default syntax;
+// it is equivalent to Dialect{}.Classify(r, f).
+func Classify(r io.Reader, f func(LineInfo) error) error { return Dialect{}.Classify(r, f) }
+
+// Classify behaves as the Classify function, but parses r using the syntax
+// of d. A line that is joined to the lines after it by LineContinuation is
+// reported once, with the joined text. If d has an IncludeKey, the lines of
+// included files are also reported, after the line that includes them.
+func (d Dialect) Classify(r io.Reader, f func(LineInfo) error) error {
+	return parse(r, Handler{Dialect: d}, parseConfig{line: f})
+}
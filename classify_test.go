@@ -0,0 +1,81 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClassify(t *testing.T) {
+	const input = "; head\n\n[s]\nk = a\n  b\nbare\n\t\nj =\n"
+	var got []ini.LineInfo
+	if err := ini.Classify(strings.NewReader(input), func(li ini.LineInfo) error {
+		got = append(got, li)
+		return nil
+	}); err != nil {
+		t.Fatalf("Classify failed: %v", err)
+	}
+	want := []ini.LineInfo{
+		{Line: 1, Kind: ini.LineComment, Text: "; head"},
+		{Line: 2, Kind: ini.LineBlank, Text: ""},
+		{Line: 3, Kind: ini.LineSection, Text: "[s]"},
+		{Line: 4, Kind: ini.LineKey, Text: "k = a"},
+		{Line: 5, Kind: ini.LineContinuation, Text: "  b"},
+		{Line: 6, Kind: ini.LineKey, Text: "bare"},
+		{Line: 7, Kind: ini.LineBlank, Text: "\t"},
+		{Line: 8, Kind: ini.LineKey, Text: "j ="},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Classify (-want, +got)\n%s", diff)
+	}
+
+	errStop := errors.New("stop")
+	n := 0
+	err := ini.Classify(strings.NewReader(input), func(ini.LineInfo) error {
+		if n++; n == 3 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop || n != 3 {
+		t.Errorf("Classify: got %v after %d lines, want %v after 3", err, n, errStop)
+	}
+}
+
+func TestClassifyDialect(t *testing.T) {
+	const input = "# note\nnet={\n  k = a \\\n    b\n}\n"
+	var got []ini.LineInfo
+	d := ini.Dialect{HashComments: true, BraceBlocks: true, LineContinuation: true}
+	if err := d.Classify(strings.NewReader(input), func(li ini.LineInfo) error {
+		got = append(got, li)
+		return nil
+	}); err != nil {
+		t.Fatalf("Classify failed: %v", err)
+	}
+	want := []ini.LineInfo{
+		{Line: 1, Kind: ini.LineComment, Text: "# note"},
+		{Line: 2, Kind: ini.LineSection, Text: "net={"},
+		{Line: 3, Kind: ini.LineKey, Text: "  k = a     b"},
+		{Line: 5, Kind: ini.LineSection, Text: "}"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Classify (-want, +got)\n%s", diff)
+	}
+}
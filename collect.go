@@ -0,0 +1,74 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+// CollectMap returns a Handler that records the values of each key in m,
+// indexed by section name and then by key name. Keys that occur before any
+// section header are recorded under the section name "". Each section header
+// adds an entry for its section to m, even if the section has no keys. If a
+// key occurs more than once in a section, its values are appended.
+func CollectMap(m map[string]map[string][]string) Handler {
+	return Handler{
+		Section: func(_ Location, name string) error {
+			if m[name] == nil {
+				m[name] = make(map[string][]string)
+			}
+			return nil
+		},
+		KeyValue: func(loc Location, key string, values []string) error {
+			sec := m[loc.Section]
+			if sec == nil {
+				sec = make(map[string][]string)
+				m[loc.Section] = sec
+			}
+			sec[key] = append(sec[key], values...)
+			return nil
+		},
+	}
+}
+
+// An Entry records a single key and its values, as delivered by a KeyValue
+// callback.
+type Entry struct {
+	Location
+	Key    string
+	Values []string
+}
+
+// CollectOrdered returns a Handler that appends an Entry to *out for each
+// key, in order of occurrence.
+func CollectOrdered(out *[]Entry) Handler {
+	return Handler{
+		KeyValue: func(loc Location, key string, values []string) error {
+			*out = append(*out, Entry{Location: loc, Key: key, Values: values})
+			return nil
+		},
+	}
+}
+
+// CollectSection returns a Handler that records the values of each key in
+// the named section in m, indexed by key name. Keys in other sections are
+// ignored. Use "" to collect the keys that occur before any section header.
+// If a key occurs more than once, its values are appended.
+func CollectSection(name string, m map[string][]string) Handler {
+	return Handler{
+		KeyValue: func(loc Location, key string, values []string) error {
+			if loc.Section == name {
+				m[key] = append(m[key], values...)
+			}
+			return nil
+		},
+	}
+}
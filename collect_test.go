@@ -0,0 +1,75 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+const collectInput = `top = 1
+[a]
+x = 2
+y = 3
+  4
+[b]
+[a]
+x = 5
+`
+
+func TestCollectMap(t *testing.T) {
+	got := make(map[string]map[string][]string)
+	if err := ini.Parse(strings.NewReader(collectInput), ini.CollectMap(got)); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := map[string]map[string][]string{
+		"":  {"top": {"1"}},
+		"a": {"x": {"2", "5"}, "y": {"3", "4"}},
+		"b": {},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CollectMap (-want, +got)\n%s", diff)
+	}
+}
+
+func TestCollectOrdered(t *testing.T) {
+	var got []ini.Entry
+	if err := ini.Parse(strings.NewReader(collectInput), ini.CollectOrdered(&got)); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []ini.Entry{
+		{Location: ini.Location{Line: 1}, Key: "top", Values: []string{"1"}},
+		{Location: ini.Location{Line: 3, Section: "a"}, Key: "x", Values: []string{"2"}},
+		{Location: ini.Location{Line: 4, Section: "a"}, Key: "y", Values: []string{"3", "4"}},
+		{Location: ini.Location{Line: 8, Section: "a"}, Key: "x", Values: []string{"5"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CollectOrdered (-want, +got)\n%s", diff)
+	}
+}
+
+func TestCollectSection(t *testing.T) {
+	got := make(map[string][]string)
+	if err := ini.Parse(strings.NewReader(collectInput), ini.CollectSection("a", got)); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := map[string][]string{"x": {"2", "5"}, "y": {"3", "4"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CollectSection (-want, +got)\n%s", diff)
+	}
+}
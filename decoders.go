@@ -0,0 +1,105 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strings"
+	"time"
+)
+
+// A Decoder converts the value of a key to a Go value.
+type Decoder func(value string) (interface{}, error)
+
+// A DecoderRegistry maps patterns of key names to decoders, so that the
+// conventions of a configuration format (for example, that keys ending in
+// "timeout" are durations) can be declared in one place rather than at each
+// use. Document.Decode and the Decoders of an Unmarshaler use a registry to
+// convert values. The zero value is an empty registry ready for use.
+type DecoderRegistry struct {
+	routes []decoderRoute
+}
+
+type decoderRoute struct {
+	pattern string
+	dec     Decoder
+}
+
+// Register adds a decoder for the keys matching pattern, using the syntax of
+// path.Match. A pattern without a "/" is matched against the name of the key
+// alone; otherwise it is matched against the path of the key as given by
+// KeyPath. If more than one pattern matches a key, the first one registered
+// is used. Register panics if pattern is malformed.
+func (r *DecoderRegistry) Register(pattern string, dec Decoder) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		panic(fmt.Sprintf("ini: invalid key pattern %q: %v", pattern, err))
+	}
+	r.routes = append(r.routes, decoderRoute{pattern: pattern, dec: dec})
+}
+
+// Lookup returns the decoder registered for the given key in section, or nil
+// if there is none.
+func (r *DecoderRegistry) Lookup(section, key string) Decoder {
+	for _, rt := range r.routes {
+		name := key
+		if strings.Contains(rt.pattern, "/") {
+			name = KeyPath(section, key)
+		}
+		if ok, _ := path.Match(rt.pattern, name); ok {
+			return rt.dec
+		}
+	}
+	return nil
+}
+
+// Decode decodes value using the decoder registered for the given key in
+// section. If no decoder is registered for the key, Decode returns value.
+func (r *DecoderRegistry) Decode(section, key, value string) (interface{}, error) {
+	if dec := r.Lookup(section, key); dec != nil {
+		return dec(value)
+	}
+	return value, nil
+}
+
+// DecodeDuration is a Decoder for durations. It accepts the formats of
+// UnitSeconds, and returns a time.Duration.
+func DecodeDuration(value string) (interface{}, error) {
+	v, err := UnitSeconds.Canonical(value)
+	if err != nil {
+		return nil, err
+	}
+	return time.Duration(v * float64(time.Second)), nil
+}
+
+// DecodeSize is a Decoder for sizes. It accepts the formats of UnitBytes,
+// and returns an int64 number of bytes. A size that is not a whole number
+// of bytes, such as "1.5", is an error.
+func DecodeSize(value string) (interface{}, error) {
+	v, err := UnitBytes.Canonical(value)
+	if err != nil {
+		return nil, err
+	} else if v != math.Trunc(v) {
+		return nil, fmt.Errorf("size %q is not a whole number of bytes", value)
+	} else if math.Abs(v) >= math.MaxInt64 {
+		return nil, fmt.Errorf("size %q is too large", value)
+	}
+	return int64(v), nil
+}
+
+// DecodeBool is a Decoder for Boolean values. It accepts the formats of
+// ParseBool, and returns a bool.
+func DecodeBool(value string) (interface{}, error) { return ParseBool(value) }
@@ -0,0 +1,192 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecoderRegistry(t *testing.T) {
+	var r ini.DecoderRegistry
+	r.Register("*.timeout", ini.DecodeDuration)
+	r.Register("*.bytes", ini.DecodeSize)
+	r.Register("flags/*", ini.DecodeBool)
+	r.Register("*", func(v string) (interface{}, error) { return "other:" + v, nil })
+
+	tests := []struct {
+		section, key, value string
+		want                interface{}
+	}{
+		{"server", "read.timeout", "1m30s", 90 * time.Second},
+		{"server", "write.timeout", "2.5", 2500 * time.Millisecond},
+		{"cache", "max.bytes", "4KiB", int64(4096)},
+		{"flags", "beta", "yes", true},
+		{"server", "name", "x", "other:x"},
+	}
+	for _, test := range tests {
+		got, err := r.Decode(test.section, test.key, test.value)
+		if err != nil {
+			t.Errorf("Decode(%q, %q, %q) failed: %v", test.section, test.key, test.value, err)
+		} else if got != test.want {
+			t.Errorf("Decode(%q, %q, %q): got %v (%T), want %v (%T)",
+				test.section, test.key, test.value, got, got, test.want, test.want)
+		}
+	}
+	if _, err := r.Decode("server", "read.timeout", "soon"); err == nil {
+		t.Error("Decode of an invalid duration: got nil error")
+	}
+	if got, err := ini.DecodeSize("1.5KiB"); err != nil || got != int64(1536) {
+		t.Errorf("DecodeSize(1.5KiB): got %v, %v, want 1536", got, err)
+	}
+	for _, v := range []string{"1.5", "0.3KiB", "9EiB"} {
+		if got, err := ini.DecodeSize(v); err == nil {
+			t.Errorf("DecodeSize(%q): got %v, want error", v, got)
+		}
+	}
+
+	var empty ini.DecoderRegistry
+	if got, err := empty.Decode("s", "k", "v"); err != nil || got != "v" {
+		t.Errorf("Decode with no decoders: got %v, %v, want v", got, err)
+	}
+	if empty.Lookup("s", "k") != nil {
+		t.Error("Lookup with no decoders: got non-nil")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Register with a bad pattern did not panic")
+		}
+	}()
+	r.Register("[", ini.DecodeBool)
+}
+
+func TestDecoderRegistryDocument(t *testing.T) {
+	var r ini.DecoderRegistry
+	r.Register("*timeout", ini.DecodeDuration)
+	doc, err := ini.Load(strings.NewReader("[server]\ntimeout = 1m\nname = x\nbad.timeout = soon\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	tests := []struct {
+		reg          *ini.DecoderRegistry
+		section, key string
+		want         interface{}
+		ok           bool
+	}{
+		{&r, "server", "timeout", time.Minute, true},
+		{&r, "server", "name", "x", true},
+		{nil, "server", "timeout", "1m", true},
+		{&r, "server", "missing", nil, false},
+		{&r, "nonesuch", "timeout", nil, false},
+	}
+	for _, test := range tests {
+		got, ok, err := doc.Decode(test.reg, test.section, test.key)
+		if err != nil || got != test.want || ok != test.ok {
+			t.Errorf("Decode(%q, %q): got %v, %v, %v; want %v, %v",
+				test.section, test.key, got, ok, err, test.want, test.ok)
+		}
+	}
+	_, ok, err := doc.Decode(&r, "server", "bad.timeout")
+	if e, isVE := err.(*ini.ValidationError); !ok || !isVE || e.Key != "bad.timeout" || e.Line != 4 {
+		t.Errorf("Decode invalid: got %v, %v, want a validation error at line 4", ok, err)
+	}
+}
+
+func TestDecoderRegistryUnmarshal(t *testing.T) {
+	type config struct {
+		Timeout time.Duration `ini:"timeout"`
+		Server  struct {
+			Limit   int32           `ini:"limit.bytes"`
+			Waits   []time.Duration `ini:"wait.timeout"`
+			Enabled bool            `ini:"enabled"`
+		} `ini:"server"`
+	}
+	var r ini.DecoderRegistry
+	r.Register("*timeout", ini.DecodeDuration)
+	r.Register("*.bytes", ini.DecodeSize)
+	u := ini.Unmarshaler{Dialect: ini.Dialect{ListValues: true}, Decoders: &r}
+
+	const input = `timeout = 1m30s
+[server]
+limit.bytes = 2KiB
+wait.timeout = 1s, 2s
+wait.timeout = 500ms
+enabled = on
+`
+	var got config
+	if err := u.Unmarshal(strings.NewReader(input), &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	var want config
+	want.Timeout = 90 * time.Second
+	want.Server.Limit = 2048
+	want.Server.Waits = []time.Duration{time.Second, 2 * time.Second, 500 * time.Millisecond}
+	want.Server.Enabled = true
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unmarshal (-want, +got):\n%s", diff)
+	}
+
+	for _, test := range []struct {
+		input, key string
+	}{
+		{"timeout = soon\n", "timeout"},
+		{"timeout = 1s, 2s\n", "timeout"},
+		{"[server]\nlimit.bytes = lots\n", "limit.bytes"},
+		{"[server]\nlimit.bytes = 4GiB\n", "limit.bytes"}, // overflows int32
+	} {
+		var cfg config
+		err := u.Unmarshal(strings.NewReader(test.input), &cfg)
+		if e, ok := err.(*ini.ValidationError); !ok || e.Key != test.key {
+			t.Errorf("Unmarshal(%q): got %v, want a validation error for %s", test.input, err, test.key)
+		}
+	}
+
+	// A struct field with a decoder holds a key rather than a section.
+	decodeTime := func(v string) (interface{}, error) { return time.Parse(time.DateOnly, v) }
+	var tr ini.DecoderRegistry
+	tr.Register("*since", decodeTime)
+	var times struct {
+		Since time.Time `ini:"since"`
+		Sub   struct {
+			Since *time.Time `ini:"also.since"`
+		} `ini:"sub"`
+	}
+	tu := ini.Unmarshaler{Decoders: &tr}
+	if err := tu.Unmarshal(strings.NewReader("since = 2026-01-02\n[sub]\nalso.since = 2026-03-04\n"), &times); err != nil {
+		t.Fatalf("Unmarshal times failed: %v", err)
+	}
+	if got := times.Since.Format(time.DateOnly); got != "2026-01-02" {
+		t.Errorf("Unmarshal since: got %s, want 2026-01-02", got)
+	}
+	if s := times.Sub.Since; s == nil || s.Format(time.DateOnly) != "2026-03-04" {
+		t.Errorf("Unmarshal also.since: got %v, want 2026-03-04", s)
+	}
+
+	// A field of a type Unmarshal does not support needs a decoder.
+	defer func() {
+		if recover() == nil {
+			t.Error("Unmarshal of a Duration without a decoder did not panic")
+		}
+	}()
+	var cfg struct {
+		D []time.Duration `ini:"d"`
+	}
+	u.Unmarshal(strings.NewReader(""), &cfg)
+}
@@ -0,0 +1,597 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A Dialect describes optional extensions to the INI syntax accepted by the
+// parser. The zero value selects the syntax documented for Parse.
+type Dialect struct {
+	// If EscapedBrackets is true, a section name may contain square brackets
+	// and backslashes escaped by a backslash. For example:
+	//
+	//	[module\[1\]]
+	//
+	// denotes a section named "module[1]". Unescaped brackets in the name are
+	// still reported as errors. Backslashes in keys and values are not
+	// affected.
+	EscapedBrackets bool
+
+	// If SectionEnd is true, the section headers "[end]" and "[/name]" mark
+	// the end of the current section, and subsequent keys belong to no section
+	// as if they preceded the first section header. The "[/name]" form must
+	// name the current section. An end marker is reported as a Section
+	// callback with the name "". For example:
+	//
+	//	[server]
+	//	port = 80
+	//	[/server]
+	//	timeout = 10  ; not in any section
+	SectionEnd bool
+
+	// If NestedSections is true, a section header indented more deeply than
+	// the previous section header denotes a subsection of it. Indentation is
+	// measured by counting each leading space or tab as one column, or each
+	// leading whitespace character if UniformSpace is true. The name of a
+	// subsection is delivered as a path of the names of its ancestors and
+	// itself, separated by slashes. For example:
+	//
+	//	[parent]
+	//	  [child]
+	//	    [grandchild]
+	//	  [sibling]
+	//
+	// denotes sections "parent", "parent/child", "parent/child/grandchild",
+	// and "parent/sibling".
+	NestedSections bool
+
+	// If ASCIISpace is true, only the ASCII space, tab, newline, vertical
+	// tab, form feed, and carriage return characters are whitespace. By
+	// default, any character for which unicode.IsSpace reports true is
+	// whitespace. The policy applies to the trimming of names and values, the
+	// normalization of whitespace in names, and to indentation if
+	// UniformSpace is true.
+	ASCIISpace bool
+
+	// If UniformSpace is true, a line is indented if it begins with any
+	// whitespace character, under the same policy used for trimming (see
+	// ASCIISpace). By default, only a leading space or tab indents a line, so
+	// that a line beginning with a no-break space is trimmed but does not
+	// continue the values of the preceding key. With UniformSpace, it does.
+	UniformSpace bool
+
+	// If StrictKeys is true, a key name that contains a control character or
+	// "=", or that begins with "[", is reported as a syntax error.
+	StrictKeys bool
+
+	// If HashComments is true, a line beginning with "#" is a comment, as is
+	// a line beginning with ";".
+	HashComments bool
+
+	// If LineContinuation is true, a line ending in a backslash is joined
+	// with the line following it, with the backslash removed. The joined line
+	// is reported at the location of its first line. Note that this affects
+	// values that end in a backslash, such as some Windows paths.
+	LineContinuation bool
+
+	// If FoldKeys is true, key names are converted to lower case, so that
+	// keys differing only in case are delivered with the same name. Section
+	// names are not affected.
+	FoldKeys bool
+
+	// If Synonyms is not nil, a key whose name is present in Synonyms is
+	// delivered with the corresponding name instead. If FoldKeys is true, the
+	// names in Synonyms should be lower case.
+	Synonyms map[string]string
+
+	// If BraceBlocks is true, a line of the form "name = {" opens a block
+	// that is delivered as a section, and a line containing only "}" closes
+	// it. Blocks may be nested, and the name of a nested block is delivered
+	// as a path of the names of its enclosing blocks and itself, separated by
+	// slashes. When a block closes, a Section callback reports the name of
+	// the enclosing block, or "" if there is none. For example:
+	//
+	//	ctrl_interface=/var/run/wpa_supplicant
+	//	network={
+	//	    ssid="home"
+	//	}
+	//
+	// delivers the key "ssid" in section "network", followed by a Section
+	// callback with name "". A section header may not appear inside a block.
+	BraceBlocks bool
+
+	// If IncludeKey is not empty, a key with this name is not delivered.
+	// Instead, its value is a glob pattern naming files whose contents are
+	// parsed in its place, in lexicographic order of their paths, as if they
+	// appeared in the current section. A pattern without glob metacharacters
+	// must name an existing file, but a glob pattern may match no files. A
+	// relative pattern in an included file is resolved against the directory
+	// containing that file, and one in the main input against the current
+	// working directory. Elements of an included file report its path in the File field of their
+	// Location. Included files may change the current section, and may
+	// themselves include other files.
+	IncludeKey string
+
+	// If MultilineValues is true, the indented lines following a key are
+	// joined to its value with newlines, even if they contain "=", and blank
+	// lines among them are kept as empty lines. Leading and trailing
+	// whitespace is removed from each line, and blank lines at the end of the
+	// block are dropped. As in Python's configparser, if the key has no value
+	// on its own line, the value begins with a newline. For example:
+	//
+	//	[options]
+	//	install_requires =
+	//	    requests >= 2.0
+	//
+	//	    click
+	//
+	// delivers the key "install_requires" with the single value
+	// "\nrequests >= 2.0\n\nclick".
+	//
+	// Indented comment lines within the block are delivered as comments, and
+	// do not end the block.
+	MultilineValues bool
+
+	// If ListValues is true, each value of a key is split at commas and
+	// newlines into separate values. Leading and trailing whitespace is
+	// removed from each value, and empty values are dropped. A key with no
+	// non-empty values is delivered with the single value "".
+	ListValues bool
+
+	// If PHPValues is true, values are interpreted as by PHP's parse_ini_file
+	// in its normal scanner mode; see PHP.
+	PHPValues bool
+
+	// If Constants is not nil, an unquoted value that is exactly the name of
+	// a constant in the map is replaced by the constant's value. Constants
+	// are only replaced when PHPValues is true.
+	Constants map[string]string
+
+	// If QuotedValues is true, a value that begins and ends with a double
+	// quote is delivered without the quotes, and any whitespace between them
+	// is kept.
+	QuotedValues bool
+
+	// If PercentEscapes is true, the sequence "%n" in a value is replaced by
+	// a newline and "%%" by a single percent sign. Other uses of "%" are left
+	// unchanged, so placeholders such as "%1" are delivered as written.
+	PercentEscapes bool
+
+	// If HeaderPattern is not empty, it is a regular expression, in the
+	// syntax of the regexp package, that is matched against the name of each
+	// section header. If the name matches, the text captured by the named
+	// groups of the pattern is delivered in the Captures field of the
+	// Location for the Section callback. The name itself is delivered as
+	// usual. For example, with the pattern
+	//
+	//	^host "(?P<host>[^"]*)" port=(?P<port>\d+)$
+	//
+	// the header [host "db1" port=5432] captures "db1" as host and "5432"
+	// as port. A malformed pattern is reported as an error without
+	// reading the input.
+	HeaderPattern string
+
+	// If Normalizers is not empty, each value of a key is passed through the
+	// normalizers in order before it is delivered, after the other options
+	// of the Dialect have been applied. See TrimQuotes, DecodePercent, and
+	// UnescapeBackslashes for some common normalizations. Normalizers are
+	// not applied to the value of IncludeKey. Since functions cannot be
+	// serialized, Normalizers is omitted from the JSON encoding of a Dialect
+	// and cannot be set by ReadDialect.
+	Normalizers []Normalizer `json:"-"`
+}
+
+// InnoSetup is a Dialect for the INI files used by Inno Setup and many game
+// modding tools, in which values may be quoted to keep leading and trailing
+// spaces, and "%n" and "%%" denote a newline and a percent sign.
+var InnoSetup = Dialect{QuotedValues: true, PercentEscapes: true}
+
+// Flake8 is a Dialect for the configuration files of Python tools such as
+// flake8 and pytest. Comments begin with "#" or ";", and a key's value may
+// be a list spanning an indented block, separated by commas or newlines.
+var Flake8 = Dialect{
+	HashComments:    true,
+	MultilineValues: true,
+	ListValues:      true,
+}
+
+// Pacman is a Dialect for pacman.conf files. Comments begin with "#", and the
+// key "Include" includes the contents of other files.
+var Pacman = Dialect{
+	HashComments: true,
+	IncludeKey:   "Include",
+}
+
+// Samba is a Dialect for Samba smb.conf files. Comments begin with "#" or
+// ";", lines may be continued with a backslash, and parameter names are not
+// case sensitive. Its Synonyms map some alternative parameter names to the
+// names used in the smb.conf documentation. To add synonyms, make a copy of
+// the map rather than modifying it.
+var Samba = Dialect{
+	HashComments:     true,
+	LineContinuation: true,
+	FoldKeys:         true,
+	Synonyms: map[string]string{
+		"allow hosts": "hosts allow",
+		"browsable":   "browseable",
+		"deny hosts":  "hosts deny",
+		"directory":   "path",
+		"exec":        "preexec",
+		"public":      "guest ok",
+		"write ok":    "writeable",
+		"writable":    "writeable",
+	},
+}
+
+// ReadDialect reads a Dialect from a descriptor in INI format. Each key
+// before the first section header names a field of Dialect, ignoring case,
+// and gives its value; Boolean values are parsed by ParseBool. The keys of
+// a section named for a map field, such as Synonyms or Constants, are added
+// to that map. Fields not mentioned have their zero values. For example:
+//
+//	HashComments = yes
+//	FoldKeys = yes
+//	IncludeKey = include
+//
+//	[Synonyms]
+//	colour = color
+//
+// An unknown field or section, or an invalid value, is reported as a
+// SyntaxError. A Dialect may also be encoded and decoded as JSON by the
+// encoding/json package, using its field names as object keys.
+func ReadDialect(r io.Reader) (Dialect, error) {
+	var d Dialect
+	v := reflect.ValueOf(&d).Elem()
+	field := func(name string) (reflect.Value, bool) {
+		f := v.FieldByNameFunc(func(s string) bool { return strings.EqualFold(s, name) })
+		return f, f.IsValid()
+	}
+	err := Parse(r, Handler{
+		Section: func(loc Location, name string) error {
+			if f, ok := field(name); !ok || f.Kind() != reflect.Map {
+				return &SyntaxError{Location: loc, Desc: "unknown dialect section", Key: name}
+			} else if f.IsNil() {
+				f.Set(reflect.MakeMap(f.Type()))
+			}
+			return nil
+		},
+		KeyValue: func(loc Location, key string, values []string) error {
+			value := JoinValues(values, JoinSpace)
+			if loc.Section != "" {
+				f, _ := field(loc.Section)
+				f.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(value))
+				return nil
+			}
+			f, ok := field(key)
+			switch {
+			case ok && f.Kind() == reflect.Bool:
+				b, err := ParseBool(value)
+				if err != nil {
+					return &SyntaxError{Location: loc, Desc: "invalid Boolean value", Key: key}
+				}
+				f.SetBool(b)
+			case ok && f.Kind() == reflect.String:
+				f.SetString(value)
+			default:
+				return &SyntaxError{Location: loc, Desc: "unknown dialect field", Key: key}
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return Dialect{}, err
+	}
+	return d, nil
+}
+
+// headerPattern compiles the HeaderPattern of d, or returns nil if it is
+// empty.
+func (d Dialect) headerPattern() (*regexp.Regexp, error) {
+	if d.HeaderPattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile(d.HeaderPattern)
+}
+
+// headerCaptures returns the text captured by the named groups of re in
+// name, or nil if re is nil or does not match name.
+func headerCaptures(re *regexp.Regexp, name string) map[string]string {
+	if re == nil {
+		return nil
+	}
+	m := re.FindStringSubmatch(name)
+	if m == nil {
+		return nil
+	}
+	caps := make(map[string]string)
+	for i, g := range re.SubexpNames() {
+		if g != "" {
+			caps[g] = m[i]
+		}
+	}
+	return caps
+}
+
+// isComment reports whether clean, which has had leading and trailing
+// whitespace removed and is not empty, is a comment line.
+func (d Dialect) isComment(clean string) bool {
+	return clean[0] == ';' || (d.HashComments && clean[0] == '#')
+}
+
+// blockStart reports whether clean opens a brace block, and if so returns the
+// normalized name of the block.
+func (d Dialect) blockStart(clean string) (string, bool) {
+	if !d.BraceBlocks {
+		return "", false
+	}
+	name, rest, ok := strings.Cut(clean, "=")
+	if !ok || d.trimSpace(rest) != "{" {
+		return "", false
+	}
+	name = d.cleanKey(name)
+	return name, name != ""
+}
+
+// isBlockEnd reports whether clean closes a brace block.
+func (d Dialect) isBlockEnd(clean string) bool { return d.BraceBlocks && clean == "}" }
+
+// checkKey reports a syntax error at loc if key is not a valid key name under
+// the StrictKeys policy of d.
+func (d Dialect) checkKey(loc Location, key string) error {
+	if !d.StrictKeys {
+		return nil
+	} else if strings.HasPrefix(key, "[") || strings.ContainsRune(key, '=') ||
+		strings.IndexFunc(key, unicode.IsControl) >= 0 {
+		return syntaxError(loc, msgInvalidKey, strconv.Quote(key))
+	}
+	return nil
+}
+
+// keyName returns the name to deliver for the normalized key name.
+func (d Dialect) keyName(key string) string {
+	if d.FoldKeys {
+		key = strings.ToLower(key)
+	}
+	if alt, ok := d.Synonyms[key]; ok {
+		return alt
+	}
+	return key
+}
+
+// unquoteValue removes the double quotes around value, for QuotedValues.
+func unquoteValue(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// percentReplacer replaces escape sequences for PercentEscapes.
+var percentReplacer = strings.NewReplacer("%n", "\n", "%%", "%")
+
+// splitList splits values at commas and newlines, for ListValues.
+func splitList(values, indents []string) (out, outIndents []string) {
+	for i, v := range values {
+		for _, f := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == '\n' }) {
+			if f = strings.TrimSpace(f); f != "" {
+				out = append(out, f)
+				if indents != nil {
+					outIndents = append(outIndents, indents[i])
+				}
+			}
+		}
+	}
+	if len(out) == 0 {
+		if indents != nil {
+			outIndents = []string{""}
+		}
+		return []string{""}, outIndents
+	}
+	return out, outIndents
+}
+
+// joinLines joins text with the lines following it in buf while it ends with
+// a backslash, and returns the joined text along with the number of lines
+// that were appended to it.
+func joinLines(buf *bufio.Scanner, text string) (string, int) {
+	var n int
+	for {
+		trim := strings.TrimRight(text, " \t")
+		if !strings.HasSuffix(trim, `\`) {
+			return text, n
+		}
+		text = trim[:len(trim)-1]
+		if !buf.Scan() {
+			return text, n
+		}
+		text += buf.Text()
+		n++
+	}
+}
+
+// parseHeader returns the normalized name of the section header in clean,
+// which has had leading and trailing whitespace removed.
+func (d Dialect) parseHeader(loc Location, clean string) (string, error) {
+	if clean[len(clean)-1] != ']' || (d.EscapedBrackets && isEscaped(clean, len(clean)-1)) {
+		return "", syntaxError(loc, msgUnclosedHeader, clean[1:])
+	}
+	name := clean[1 : len(clean)-1]
+	if d.EscapedBrackets {
+		var ok bool
+		name, ok = unescapeBrackets(name)
+		if !ok {
+			return "", syntaxError(loc, msgInvalidSection, d.cleanKey(name))
+		}
+	} else if strings.ContainsAny(name, "[]") {
+		return "", syntaxError(loc, msgInvalidSection, cleanKey(name))
+	}
+	name = d.cleanKey(name)
+	if name == "" {
+		return "", syntaxError(loc, msgInvalidSection, name)
+	}
+	if d.SectionEnd {
+		if name == "end" {
+			return "", nil
+		} else if end, ok := strings.CutPrefix(name, "/"); ok {
+			if d.trimSpace(end) != loc.Section {
+				return "", syntaxError(loc, msgMismatchedEnd, name)
+			}
+			return "", nil
+		}
+	}
+	return name, nil
+}
+
+// sectionNest tracks the nesting of indented section headers.
+type sectionNest []nestLevel
+
+type nestLevel struct {
+	indent int    // the indentation of the header
+	name   string // the full path name of the section
+}
+
+// push records a section header with the given name and indentation, and
+// returns the full path name of the section.
+func (n *sectionNest) push(indent int, name string) string {
+	s := *n
+	for len(s) != 0 && s[len(s)-1].indent >= indent {
+		s = s[:len(s)-1]
+	}
+	if len(s) != 0 {
+		name = s[len(s)-1].name + "/" + name
+	}
+	*n = append(s, nestLevel{indent: indent, name: name})
+	return name
+}
+
+// isSpace reports whether r is whitespace under the policy of d.
+func (d Dialect) isSpace(r rune) bool {
+	if d.ASCIISpace {
+		return r < utf8.RuneSelf && asciiSpace[r]
+	}
+	return unicode.IsSpace(r)
+}
+
+var asciiSpace = [utf8.RuneSelf]bool{' ': true, '\t': true, '\n': true, '\v': true, '\f': true, '\r': true}
+
+// isASCIISpace reports whether c is an ASCII whitespace character.
+func isASCIISpace(c byte) bool { return c < utf8.RuneSelf && asciiSpace[c] }
+
+// trimSpace returns s without leading and trailing whitespace.
+func (d Dialect) trimSpace(s string) string { return strings.TrimFunc(s, d.isSpace) }
+
+// cleanKey returns key with leading and trailing whitespace removed, and
+// each run of internal whitespace replaced by a single space.
+func (d Dialect) cleanKey(key string) string {
+	key = d.trimSpace(key)
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c >= utf8.RuneSelf || (asciiSpace[c] && (c != ' ' || isASCIISpace(key[i+1]))) {
+			// The key may need normalization; do it the slow way.
+			return strings.Join(strings.FieldsFunc(key, d.isSpace), " ")
+		}
+	}
+	return key
+}
+
+// A lineShape describes the layout of a line of input.
+type lineShape struct {
+	clean  string // the line without leading and trailing whitespace
+	indent int    // the length in bytes of the leading whitespace
+	eq     int    // the offset of the first "=" in clean, or -1
+}
+
+// scanLine computes the shape of text in a single pass over the line.
+func (d Dialect) scanLine(text string) lineShape {
+	start, end, eq := -1, 0, -1
+	for i := 0; i < len(text); {
+		c, size := rune(text[i]), 1
+		space := isASCIISpace(text[i])
+		if c >= utf8.RuneSelf {
+			c, size = utf8.DecodeRuneInString(text[i:])
+			space = d.isSpace(c)
+		}
+		if !space {
+			if start < 0 {
+				start = i
+			}
+			if c == '=' && eq < 0 {
+				eq = i
+			}
+			end = i + size
+		}
+		i += size
+	}
+	if start < 0 {
+		return lineShape{indent: len(text), eq: -1}
+	} else if eq >= 0 {
+		eq -= start
+	}
+	return lineShape{clean: text[start:end], indent: start, eq: eq}
+}
+
+// indent returns the leading whitespace of s that counts as indentation.
+func (d Dialect) indent(s string) string {
+	if d.UniformSpace {
+		return s[:len(s)-len(strings.TrimLeftFunc(s, d.isSpace))]
+	}
+	return s[:len(s)-len(strings.TrimLeft(s, " \t"))]
+}
+
+// isEscaped reports whether s[i] is preceded by an odd number of backslashes.
+func isEscaped(s string, i int) bool {
+	n := 0
+	for i > 0 && s[i-1] == '\\' {
+		n++
+		i--
+	}
+	return n%2 == 1
+}
+
+// unescapeBrackets removes backslash escapes for brackets and backslashes from
+// s, and reports whether s is free of unescaped brackets. Other backslashes
+// are left in place.
+func unescapeBrackets(s string) (string, bool) {
+	if !strings.ContainsAny(s, `[]\`) {
+		return s, true
+	}
+	var sb strings.Builder
+	ok := true
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 < len(s) && strings.IndexByte(`[]\`, s[i+1]) >= 0 {
+				i++
+				c = s[i]
+			}
+			sb.WriteByte(c)
+		case '[', ']':
+			ok = false
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String(), ok
+}
@@ -0,0 +1,281 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// A Document is the contents of INI data held in memory, for programs that
+// prefer to query a structure rather than handle callbacks. Use Load to parse
+// a Document, or CollectDocument to build one with other Handler options.
+type Document struct {
+	// Sections holds the sections of the document in order of their first
+	// occurrence. Keys that precede the first section header belong to a
+	// section with the name "", which is first if it is present. A section
+	// whose header occurs more than once is merged into its first
+	// occurrence, and the later headers are recorded in its Repeats.
+	Sections []*Section
+
+	// Comments holds the comments at the end of the input, which do not
+	// precede any section or key.
+	Comments []string
+}
+
+// A Section is a section of a Document.
+type Section struct {
+	Location        // the location of the first header for the section
+	Name     string // the name of the section
+	Keys     []*Key // the keys of the section, in order of occurrence
+
+	// Repeats holds the later headers for the section, whose keys are merged
+	// into Keys, in order of occurrence.
+	Repeats []Repeat
+}
+
+// A Repeat is a later header for a section of a Document.
+type Repeat struct {
+	Location     // the location of the header, with its comments
+	Index    int // the number of keys of the section that precede it
+}
+
+// A Key is a key and its values in a Document.
+type Key struct {
+	Location          // the location of the key
+	Name     string   // the name of the key
+	Values   []string // the values of the key, as delivered by the parser
+}
+
+// Load parses the INI data from r into a Document. The comments preceding
+// each section header and key are recorded in its Location, as are the
+// comments inside the block of a multi-line value. Load uses the
+// default syntax; it is equivalent to Dialect{}.Load(r).
+func Load(r io.Reader) (*Document, error) { return Dialect{}.Load(r) }
+
+// Load behaves as the Load function, but parses r using the syntax of d.
+func (d Dialect) Load(r io.Reader) (*Document, error) {
+	doc := new(Document)
+	h := CollectDocument(doc)
+	h.Dialect = d
+	h.AttachComments = true
+	h.BlockComments = BlockCommentsAttach
+	if err := Parse(r, h); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// CollectDocument returns a Handler that adds the sections and keys it
+// receives to doc. Comments are added to doc.Comments; set AttachComments on
+// the handler to record them with the sections and keys they precede.
+func CollectDocument(doc *Document) Handler {
+	return Handler{
+		Comment: func(_ Location, text string) error {
+			doc.Comments = append(doc.Comments, text)
+			return nil
+		},
+		Section: func(loc Location, name string) error {
+			if s := doc.Section(name); s != nil {
+				s.Repeats = append(s.Repeats, Repeat{Location: loc, Index: len(s.Keys)})
+			} else {
+				doc.section(loc, name)
+			}
+			return nil
+		},
+		KeyValue: func(loc Location, key string, values []string) error {
+			sloc := Location{Section: loc.Section, File: loc.File, Tag: loc.Tag}
+			sec := doc.section(sloc, loc.Section)
+			sec.Keys = append(sec.Keys, &Key{Location: loc, Name: key, Values: values})
+			return nil
+		},
+	}
+}
+
+// section returns the section of d with the given name, adding it at loc if
+// it does not exist.
+func (d *Document) section(loc Location, name string) *Section {
+	if s := d.Section(name); s != nil {
+		return s
+	}
+	s := &Section{Location: loc, Name: name}
+	if name == "" {
+		d.Sections = append([]*Section{s}, d.Sections...)
+	} else {
+		d.Sections = append(d.Sections, s)
+	}
+	return s
+}
+
+// Section returns the section of d with the given name, or nil if there is
+// no such section.
+func (d *Document) Section(name string) *Section {
+	for _, s := range d.Sections {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Get returns the value of the named key in the named section, as Key.Value,
+// and reports whether the key is present.
+func (d *Document) Get(section, key string) (string, bool) {
+	if s := d.Section(section); s != nil {
+		if k := s.Key(key); k != nil {
+			return k.Value(), true
+		}
+	}
+	return "", false
+}
+
+// Decode returns the value of the named key in the named section, as Get,
+// decoded by the decoder registered for the key in reg, and reports whether
+// the key is present. If reg is nil or has no decoder for the key, the value
+// is returned as a string. An error from the decoder is reported as a
+// *ValidationError at the location of the key.
+func (d *Document) Decode(reg *DecoderRegistry, section, key string) (
+	interface{}, bool, error) {
+	s := d.Section(section)
+	if s == nil {
+		return nil, false, nil
+	}
+	k := s.Key(key)
+	if k == nil {
+		return nil, false, nil
+	}
+	v := k.Value()
+	if reg == nil {
+		return v, true, nil
+	}
+	x, err := reg.Decode(section, key, v)
+	if err != nil {
+		desc := fmt.Sprintf("invalid value %q: %v", v, err)
+		return nil, true, &ValidationError{Location: k.Location, Desc: desc, Key: key}
+	}
+	return x, true, nil
+}
+
+// Key returns the last key in s with the given name, since a later setting
+// of a key usually overrides an earlier one, or nil if there is no such key.
+func (s *Section) Key(name string) *Key {
+	for i := len(s.Keys) - 1; i >= 0; i-- {
+		if s.Keys[i].Name == name {
+			return s.Keys[i]
+		}
+	}
+	return nil
+}
+
+// Value returns the values of k joined with newlines. For a key with a
+// single value, this is that value.
+func (k *Key) Value() string { return JoinValues(k.Values, JoinNewline) }
+
+// A DocumentBuilder assembles a Document from events, for example events
+// from several parsers running concurrently. It is safe for concurrent use by
+// multiple goroutines. The zero value is ready for use.
+//
+// Each section is guarded separately, so that goroutines adding keys to
+// different sections do not contend with one another. Sections occur in the
+// order they were first added, and keys in the order they were added to
+// their section; when events are added concurrently that order depends on
+// the scheduling of the goroutines.
+type DocumentBuilder struct {
+	mu       sync.Mutex
+	shards   map[string]*docShard
+	order    []*docShard
+	comments []string
+}
+
+// A docShard is a section of a DocumentBuilder with its own lock.
+type docShard struct {
+	mu  sync.Mutex
+	sec *Section
+}
+
+// shard returns the shard for the named section, adding it at loc if it does
+// not exist, and reports whether it was added.
+func (b *DocumentBuilder) shard(loc Location, name string) (*docShard, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if s, ok := b.shards[name]; ok {
+		return s, false
+	}
+	if b.shards == nil {
+		b.shards = make(map[string]*docShard)
+	}
+	s := &docShard{sec: &Section{Location: loc, Name: name}}
+	b.shards[name] = s
+	b.order = append(b.order, s)
+	return s, true
+}
+
+// Add adds the contents of e to the document. Comment events are added to the
+// comments of the document, section events add a section if it does not
+// already exist or record a repeat of its header, and key events add a key to
+// the section named by e.Section. Add reports an error if e has an unknown
+// kind.
+func (b *DocumentBuilder) Add(e Event) error {
+	switch e.Kind {
+	case KindComment:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.comments = append(b.comments, e.Text)
+	case KindSection:
+		loc := e.Location()
+		if s, isNew := b.shard(loc, e.Name); !isNew {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			rep := Repeat{Location: loc, Index: len(s.sec.Keys)}
+			s.sec.Repeats = append(s.sec.Repeats, rep)
+		}
+	case KindKey:
+		loc := e.Location()
+		sloc := Location{Section: loc.Section, File: loc.File, Tag: loc.Tag}
+		s, _ := b.shard(sloc, e.Section)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.sec.Keys = append(s.sec.Keys, &Key{Location: loc, Name: e.Name, Values: e.Values})
+	default:
+		return fmt.Errorf("unknown event kind %q", e.Kind)
+	}
+	return nil
+}
+
+// Handler returns a Handler that adds each callback from the parser to b.
+func (b *DocumentBuilder) Handler() Handler { return EventHandler(b.Add) }
+
+// Document returns a Document containing the events added to b so far. The
+// result does not share sections or key lists with b, so b may continue to
+// be used after Document returns.
+func (b *DocumentBuilder) Document() *Document {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	doc := &Document{Comments: append([]string(nil), b.comments...)}
+	for _, s := range b.order {
+		s.mu.Lock()
+		sec := *s.sec
+		sec.Keys = append([]*Key(nil), sec.Keys...)
+		sec.Repeats = append([]Repeat(nil), sec.Repeats...)
+		s.mu.Unlock()
+		if sec.Name == "" {
+			doc.Sections = append([]*Section{&sec}, doc.Sections...)
+		} else {
+			doc.Sections = append(doc.Sections, &sec)
+		}
+	}
+	return doc
+}
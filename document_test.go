@@ -0,0 +1,225 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoad(t *testing.T) {
+	const input = `top = 1
+; About alpha.
+[alpha]
+x = 1
+y = 2
+  3
+[beta]
+[alpha]
+x = 4
+; trailing
+`
+	doc, err := ini.Dialect{MultilineValues: true}.Load(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	var names []string
+	for _, s := range doc.Sections {
+		names = append(names, s.Name)
+	}
+	if diff := cmp.Diff(names, []string{"", "alpha", "beta"}); diff != "" {
+		t.Errorf("Sections (-got, +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(doc.Comments, []string{"; trailing"}); diff != "" {
+		t.Errorf("Comments (-got, +want):\n%s", diff)
+	}
+
+	alpha := doc.Section("alpha")
+	if alpha == nil {
+		t.Fatal(`Section("alpha") is missing`)
+	}
+	if got, want := alpha.Line, 3; got != want {
+		t.Errorf("alpha line: got %d, want %d", got, want)
+	}
+	if diff := cmp.Diff(alpha.Comments, []string{"; About alpha."}); diff != "" {
+		t.Errorf("alpha comments (-got, +want):\n%s", diff)
+	}
+	if got, want := len(alpha.Keys), 3; got != want {
+		t.Errorf("alpha keys: got %d, want %d", got, want)
+	}
+	if k := alpha.Key("x"); k == nil {
+		t.Error(`Key("x") is missing`)
+	} else if got, want := k.Value(), "4"; got != want {
+		t.Errorf(`Key("x"): got %q, want %q`, got, want)
+	}
+	if k := alpha.Key("nonesuch"); k != nil {
+		t.Errorf(`Key("nonesuch"): got %+v, want nil`, k)
+	}
+	if doc.Section("nonesuch") != nil {
+		t.Error(`Section("nonesuch"): got a section, want nil`)
+	}
+
+	tests := []struct {
+		section, key string
+		want         string
+		ok           bool
+	}{
+		{"", "top", "1", true},
+		{"alpha", "y", "2\n3", true},
+		{"beta", "x", "", false},
+		{"gamma", "x", "", false},
+	}
+	for _, tc := range tests {
+		got, ok := doc.Get(tc.section, tc.key)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("Get(%q, %q): got (%q, %v), want (%q, %v)",
+				tc.section, tc.key, got, ok, tc.want, tc.ok)
+		}
+	}
+
+	if _, err := ini.Load(strings.NewReader("[bad\n")); err == nil {
+		t.Error("Load: got nil error for invalid input")
+	}
+}
+
+func TestDocumentBuilder(t *testing.T) {
+	var b ini.DocumentBuilder
+
+	// Parse several inputs concurrently, each contributing keys to a section
+	// of its own and to a section they share.
+	const n = 8
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			input := fmt.Sprintf("[own%d]\nk = %d\n[shared]\nk%d = %d\n", i, i, i, i)
+			errs[i] = ini.Parse(strings.NewReader(input), b.Handler())
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Parse %d failed: %v", i, err)
+		}
+	}
+	if err := b.Add(ini.Event{Kind: ini.KindKey, Name: "top", Values: []string{"yes"}}); err != nil {
+		t.Errorf("Add key failed: %v", err)
+	}
+	if err := b.Add(ini.Event{Kind: "bogus"}); err == nil {
+		t.Error("Add: got nil error for unknown kind")
+	}
+
+	doc := b.Document()
+	if got, want := len(doc.Sections), n+2; got != want {
+		t.Fatalf("Got %d sections, want %d", got, want)
+	}
+	if got := doc.Sections[0].Name; got != "" {
+		t.Errorf("First section: got %q, want %q", got, "")
+	}
+	for i := 0; i < n; i++ {
+		want := fmt.Sprint(i)
+		if got, ok := doc.Get(fmt.Sprintf("own%d", i), "k"); !ok || got != want {
+			t.Errorf("Get own%d/k: got (%q, %v), want (%q, true)", i, got, ok, want)
+		}
+	}
+	var keys []string
+	for _, k := range doc.Section("shared").Keys {
+		keys = append(keys, k.Name)
+	}
+	sort.Strings(keys)
+	if diff := cmp.Diff(keys, []string{"k0", "k1", "k2", "k3", "k4", "k5", "k6", "k7"}); diff != "" {
+		t.Errorf("Shared keys (-got, +want):\n%s", diff)
+	}
+
+	// The document is a snapshot, unaffected by later additions.
+	b.Add(ini.Event{Kind: ini.KindKey, Section: "shared", Name: "late"})
+	if got, want := len(doc.Section("shared").Keys), n; got != want {
+		t.Errorf("Snapshot has %d shared keys, want %d", got, want)
+	}
+	if got, want := len(b.Document().Section("shared").Keys), n+1; got != want {
+		t.Errorf("Builder has %d shared keys, want %d", got, want)
+	}
+}
+
+func TestDocumentRepeats(t *testing.T) {
+	const input = `; c1
+[a]
+x = 1
+
+[b]
+y = 2
+
+; c3
+[a]
+z = 3
+
+; c5
+[a]
+`
+	doc, err := ini.Load(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	a := doc.Section("a")
+	want := []ini.Repeat{
+		{Location: ini.Location{Line: 9, Section: "b", Comments: []string{"; c3"}}, Index: 1},
+		{Location: ini.Location{Line: 13, Section: "a", Comments: []string{"; c5"}}, Index: 2},
+	}
+	if diff := cmp.Diff(want, a.Repeats); diff != "" {
+		t.Errorf("Repeats (-want, +got):\n%s", diff)
+	}
+
+	// The keys of a section are written together, but the repeated headers
+	// and their comments are kept.
+	const output = `; c1
+[a]
+x = 1
+
+; c3
+[a]
+z = 3
+
+; c5
+[a]
+
+[b]
+y = 2
+`
+	var buf strings.Builder
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if diff := cmp.Diff(output, buf.String()); diff != "" {
+		t.Errorf("WriteTo (-want, +got):\n%s", diff)
+	}
+
+	var b ini.DocumentBuilder
+	if err := ini.Parse(strings.NewReader(input), b.Handler()); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := len(b.Document().Section("a").Repeats); got != 2 {
+		t.Errorf("DocumentBuilder: got %d repeats, want 2", got)
+	}
+}
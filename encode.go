@@ -0,0 +1,457 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// An Encoder writes INI data to an output stream, in a syntax that the parser
+// accepts for its Dialect.
+//
+// Values that span multiple lines are written as indented continuation lines,
+// which requires MultilineValues, or with "%n" escapes if PercentEscapes is
+// true. If ListValues is true, the values of a key are written as a
+// comma-separated list on a single line; otherwise each value is written as a
+// separate setting of the key. Values are escaped as the Dialect requires:
+// with PercentEscapes, "%" is written as "%%"; with PHPValues, a value that
+// PHP would alter is written in double quotes; and with QuotedValues, a value
+// with leading or trailing whitespace is written in double quotes. Otherwise
+// leading and trailing whitespace in values is not preserved. The
+// ValueComments of a key are written in place among the lines of its value,
+// when it is written by the Handler of the encoder or from a Document.
+//
+// A section or key name, comment, or value that the Dialect cannot read back
+// as written is reported as an error. For example, a name with leading or
+// trailing whitespace, a key containing "=", the IncludeKey of the Dialect,
+// and, with LineContinuation, a line ending in a backslash are errors. Since
+// the parser merges consecutive settings of a key, a key with an empty value
+// that follows another with the same name is written as a bare key, without
+// "=", to keep it separate; a key with other values cannot follow one with
+// the same name. With SectionEnd, the section name "" is written as the end
+// marker "[end]".
+type Encoder struct {
+	// Dialect is the syntax of the output.
+	Dialect Dialect
+
+	// If NumberedKeys is true, a key with more than one value is written as
+	// a run of numbered keys "key.1", "key.2", and so on, which a Handler
+	// with NumberedKeys reads back as a single key. This takes precedence
+	// over ListValues.
+	NumberedKeys bool
+
+	w     io.Writer
+	wrote bool
+	last  string // the delivered name of the key on the last line, or ""
+}
+
+// NewEncoder returns an Encoder that writes to w using the default syntax.
+func NewEncoder(w io.Writer) *Encoder { return &Encoder{w: w} }
+
+// Comment writes a comment line with the given text. If text is not already
+// a comment in the dialect of e, a comment marker is added to it.
+func (e *Encoder) Comment(text string) error {
+	text, err := e.commentLine(text)
+	if err != nil {
+		return err
+	}
+	return e.line(text)
+}
+
+// commentLine returns text as a comment line in the dialect of e.
+func (e *Encoder) commentLine(text string) (string, error) {
+	if strings.Contains(text, "\n") {
+		return "", fmt.Errorf("comment %q spans multiple lines", text)
+	}
+	if clean := e.Dialect.trimSpace(text); clean == "" || !e.Dialect.isComment(clean) {
+		text = "; " + text
+	}
+	return text, e.checkLine(text)
+}
+
+// Section writes a section header for name. Section headers other than the
+// first output are preceded by a blank line.
+func (e *Encoder) Section(name string) error { return e.header(nil, name) }
+
+// header writes a section header for name, preceded by comments.
+func (e *Encoder) header(comments []string, name string) error {
+	text, err := e.headerLine(name)
+	if err != nil {
+		return err
+	}
+	if e.wrote {
+		if err := e.line(""); err != nil {
+			return err
+		}
+	}
+	if err := e.comments(comments); err != nil {
+		return err
+	}
+	return e.line(text)
+}
+
+// bracketEscaper escapes section names for EscapedBrackets.
+var bracketEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+
+// headerLine returns the header line for the named section, or an error if
+// the name cannot be written in the dialect of e.
+func (e *Encoder) headerLine(name string) (string, error) {
+	d := e.Dialect
+	if name == "" && d.SectionEnd {
+		return "[end]", nil
+	}
+	bad := func(why string) (string, error) {
+		return "", fmt.Errorf("section name %q %s", name, why)
+	}
+	switch {
+	case name == "":
+		return bad("is empty")
+	case d.cleanKey(name) != name:
+		return bad("has extra whitespace")
+	case d.SectionEnd && (name == "end" || strings.HasPrefix(name, "/")):
+		return bad("is an end marker")
+	case d.EscapedBrackets:
+		name = bracketEscaper.Replace(name)
+	case strings.ContainsAny(name, "[]"):
+		return bad("contains a bracket")
+	}
+	return "[" + name + "]", nil
+}
+
+// checkKey reports an error if key cannot be written in the dialect of e.
+func (e *Encoder) checkKey(key string) error {
+	d := e.Dialect
+	bad := func(why string) error { return fmt.Errorf("key %q %s", key, why) }
+	switch {
+	case key == "":
+		return bad("is empty")
+	case d.cleanKey(key) != key:
+		return bad("has extra whitespace")
+	case strings.ContainsRune(key, '='):
+		return bad("contains \"=\"")
+	case key[0] == '[' || d.isComment(key):
+		return bad("begins a header or comment")
+	case d.IncludeKey != "" && d.keyName(key) == d.IncludeKey:
+		return bad("includes files")
+	}
+	return nil
+}
+
+// comments writes a comment line for each of comments.
+func (e *Encoder) comments(comments []string) error {
+	for _, c := range comments {
+		if err := e.Comment(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// KeyValue writes the setting of key to values. A key with no values is
+// written with an empty value.
+func (e *Encoder) KeyValue(key string, values []string) error {
+	return e.keyValue(key, values, nil, false)
+}
+
+// keyValue writes the setting of key to values, with comments in the block
+// of its value. With MultilineValues or ListValues, the comments are written
+// with the first value; otherwise each is written after the value its Offset
+// follows.
+//
+// The parser merges consecutive settings of a key, so a key that follows
+// another with the same name is written as a bare key, which the parser
+// delivers separately, if its value is empty, and is otherwise an error. If
+// bare is true, an empty value is written as a bare key regardless, so that
+// a following key with the same name stays separate.
+func (e *Encoder) keyValue(key string, values []string, vcs []ValueComment, bare bool) error {
+	if err := e.checkKey(key); err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		values = []string{""}
+	}
+	name := e.Dialect.keyName(key)
+	empty := len(values) == 1 && values[0] == ""
+	if name == e.last && !empty {
+		return fmt.Errorf("key %q would be merged with the setting before it", key)
+	}
+	if empty && (bare || name == e.last) {
+		if err := e.checkLine(key); err != nil {
+			return err
+		} else if err := e.line(key); err != nil {
+			return err
+		}
+		for _, vc := range vcs {
+			if err := e.blockComment(vc.Text); err != nil {
+				return err
+			}
+		}
+		return nil // a bare key is delivered at once, so e.last stays ""
+	}
+	if e.NumberedKeys && len(values) > 1 {
+		for i, v := range values {
+			if i > 0 {
+				vcs = nil
+			}
+			if err := e.setting(key+"."+strconv.Itoa(i+1), v, vcs); err != nil {
+				return err
+			}
+		}
+		return nil // a numbered run ends at the next key, so e.last stays ""
+	}
+	if e.Dialect.ListValues {
+		for _, v := range values {
+			if strings.ContainsAny(v, ",\n") {
+				return fmt.Errorf("list value %q of key %q contains a separator", v, key)
+			}
+		}
+		if err := e.setting(key, strings.Join(values, ", "), vcs); err != nil {
+			return err
+		}
+		e.last = name
+		return nil
+	}
+	for i, v := range values {
+		here := vcs
+		if e.Dialect.MultilineValues {
+			vcs = nil
+		} else if i < len(values)-1 {
+			// Each value is a line of its own; see ValueComment.
+			n := 0
+			for n < len(vcs) && vcs[n].Offset <= i+1 {
+				n++
+			}
+			here, vcs = vcs[:n], vcs[n:]
+		}
+		if err := e.setting(key, v, here); err != nil {
+			return err
+		}
+	}
+	e.last = name
+	return nil
+}
+
+// setting writes a single setting of key to value. Each of vcs is written
+// after the line of the value at its offset, or after the last line.
+func (e *Encoder) setting(key, value string, vcs []ValueComment) error {
+	d := e.Dialect
+	if d.PercentEscapes {
+		value = percentEscaper.Replace(value)
+	}
+	lines := strings.Split(value, "\n")
+	if len(lines) > 1 && !d.MultilineValues {
+		return fmt.Errorf("value of key %q spans multiple lines", key)
+	}
+	for _, line := range lines[1:] {
+		if clean := d.trimSpace(line); clean != "" && d.isComment(clean) {
+			return fmt.Errorf("value of key %q has a line %q that begins a comment", key, line)
+		}
+	}
+	v, err := e.quote(lines[0])
+	if err != nil {
+		return fmt.Errorf("value of key %q: %w", key, err)
+	}
+	first := key + " ="
+	if v != "" {
+		first += " " + v
+	}
+	if err := e.checkLine(first); err != nil {
+		return err
+	}
+	for _, line := range lines[1:] {
+		if err := e.checkLine(line); err != nil {
+			return err
+		}
+	}
+	if err := e.line(first); err != nil {
+		return err
+	}
+	for i, line := range lines[1:] {
+		for len(vcs) != 0 && vcs[0].Offset <= i+1 {
+			if err := e.blockComment(vcs[0].Text); err != nil {
+				return err
+			}
+			vcs = vcs[1:]
+		}
+		if line != "" {
+			line = "    " + line
+		}
+		if err := e.line(line); err != nil {
+			return err
+		}
+	}
+	for _, vc := range vcs {
+		if err := e.blockComment(vc.Text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// percentEscaper escapes values for PercentEscapes.
+var percentEscaper = strings.NewReplacer("%", "%%", "\n", "%n")
+
+// quote returns the text to write for the first line of a value, quoted as
+// needed to read it back in the dialect of e.
+func (e *Encoder) quote(v string) (string, error) {
+	d := e.Dialect
+	spaced := d.trimSpace(v) != v
+	if d.PHPValues {
+		if !spaced && d.phpValue(v) == v {
+			return v, nil
+		}
+		return `"` + phpEscaper.Replace(v) + `"`, nil
+	}
+	quoted := len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"'
+	opens := d.BraceBlocks && d.trimSpace(v) == "{"
+	if d.QuotedValues && (spaced || quoted || opens) {
+		return `"` + v + `"`, nil
+	} else if opens {
+		return "", errors.New(`value "{" opens a block`)
+	}
+	return v, nil
+}
+
+// phpEscaper escapes values in double quotes for PHPValues.
+var phpEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// checkLine reports an error if text would not be read back as a single line
+// in the dialect of e.
+func (e *Encoder) checkLine(text string) error {
+	if e.Dialect.LineContinuation && strings.HasSuffix(strings.TrimRight(text, " \t"), `\`) {
+		return fmt.Errorf("line %q ends with a backslash", text)
+	}
+	return nil
+}
+
+// blockComment writes a comment line with the given text inside the block of
+// a value, indenting it if necessary.
+func (e *Encoder) blockComment(text string) error {
+	text, err := e.commentLine(text)
+	if err != nil {
+		return err
+	}
+	if e.Dialect.indent(text) == "" {
+		text = "    " + text
+	}
+	return e.line(text)
+}
+
+// line writes text followed by a newline.
+func (e *Encoder) line(text string) error {
+	e.wrote, e.last = true, ""
+	_, err := io.WriteString(e.w, text+"\n")
+	return err
+}
+
+// Handler returns a Handler that writes each callback from the parser to e,
+// including the comments attached to sections and keys, so that parsing with
+// the handler copies its input to the output of e. The handler reads numbered
+// keys if e.NumberedKeys is true.
+func (e *Encoder) Handler() Handler {
+	return Handler{
+		Dialect:        e.Dialect,
+		NumberedKeys:   e.NumberedKeys,
+		AttachComments: true,
+		BlockComments:  BlockCommentsAttach,
+		Comment:        func(_ Location, text string) error { return e.Comment(text) },
+		Section: func(loc Location, name string) error {
+			return e.header(loc.Comments, name)
+		},
+		KeyValue: func(loc Location, key string, values []string) error {
+			if err := e.comments(loc.Comments); err != nil {
+				return err
+			}
+			return e.keyValue(key, values, loc.ValueComments, false)
+		},
+	}
+}
+
+// Encode writes the contents of doc to e. The comments attached to each
+// section and key are written before it, and the comments of doc are written
+// at the end. The repeated headers of a section are written among its keys,
+// as they occurred.
+func (e *Encoder) Encode(doc *Document) error {
+	for _, s := range doc.Sections {
+		var err error
+		if s.Name == "" {
+			err = e.comments(s.Comments)
+		} else {
+			err = e.header(s.Comments, s.Name)
+		}
+		if err != nil {
+			return err
+		}
+		reps := s.Repeats
+		for i, k := range s.Keys {
+			for len(reps) != 0 && reps[0].Index <= i {
+				if err := e.header(reps[0].Comments, s.Name); err != nil {
+					return err
+				}
+				reps = reps[1:]
+			}
+			if err := e.comments(k.Comments); err != nil {
+				return err
+			} else if err := e.keyValue(k.Name, k.Values, k.ValueComments, e.isolate(s, i)); err != nil {
+				return err
+			}
+		}
+		for _, r := range reps {
+			if err := e.header(r.Comments, s.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return e.comments(doc.Comments)
+}
+
+// isolate reports whether the key at index i of s is followed directly by a
+// key that the parser would merge with it.
+func (e *Encoder) isolate(s *Section, i int) bool {
+	if i+1 >= len(s.Keys) {
+		return false
+	}
+	for _, r := range s.Repeats {
+		if r.Index == i+1 {
+			return false // a header separates them
+		}
+	}
+	next := s.Keys[i+1]
+	return len(next.Comments) == 0 && e.Dialect.keyName(next.Name) == e.Dialect.keyName(s.Keys[i].Name)
+}
+
+// WriteTo writes the contents of d to w using the default syntax. It
+// implements the io.WriterTo interface.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	err := NewEncoder(cw).Encode(d)
+	return cw.n, err
+}
+
+// countWriter is an io.Writer that counts the bytes written to w.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(data []byte) (int, error) {
+	n, err := c.w.Write(data)
+	c.n += int64(n)
+	return n, err
+}
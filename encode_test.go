@@ -0,0 +1,413 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestEncoder(t *testing.T) {
+	const input = `top = 1
+; About alpha.
+[alpha]
+x = 1
+  ; inner
+y =
+  two
+  three
+[beta]
+; About z.
+z = a
+z = b
+; trailing
+`
+	const want = `top = 1
+
+; About alpha.
+[alpha]
+x = 1
+  ; inner
+y =
+    two
+    three
+
+[beta]
+; About z.
+z = a
+z = b
+; trailing
+`
+	d := ini.Dialect{MultilineValues: true}
+
+	// Copy the input through a handler.
+	var buf strings.Builder
+	enc := ini.NewEncoder(&buf)
+	enc.Dialect = d
+	if err := ini.Parse(strings.NewReader(input), enc.Handler()); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Handler output (-want, +got):\n%s", diff)
+	}
+
+	// Write a loaded document, and check that it round-trips.
+	doc, err := d.Load(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	buf.Reset()
+	enc = ini.NewEncoder(&buf)
+	enc.Dialect = d
+	if err := enc.Encode(doc); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Encode output (-want, +got):\n%s", diff)
+	}
+	m1 := make(map[string]map[string][]string)
+	m2 := make(map[string]map[string][]string)
+	if err := ini.Parse(strings.NewReader(input), ini.Handler{Dialect: d, KeyValue: ini.CollectMap(m1).KeyValue}); err != nil {
+		t.Fatalf("Parse input failed: %v", err)
+	}
+	if err := ini.Parse(strings.NewReader(buf.String()), ini.Handler{Dialect: d, KeyValue: ini.CollectMap(m2).KeyValue}); err != nil {
+		t.Fatalf("Parse output failed: %v", err)
+	}
+	if diff := cmp.Diff(m1, m2); diff != "" {
+		t.Errorf("Round trip (-input, +output):\n%s", diff)
+	}
+
+	// Without MultilineValues, a value spanning lines cannot be written.
+	if _, err := doc.WriteTo(&buf); err == nil {
+		t.Error("WriteTo: got nil error for a multi-line value")
+	}
+}
+
+func TestEncoderBlockComments(t *testing.T) {
+	const input = `[options]
+install_requires =
+    requests
+    ; pinned for now
+    click
+
+    six
+    ; end of list
+next = 1
+`
+	d := ini.Dialect{MultilineValues: true}
+	doc, err := d.Load(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	var buf strings.Builder
+	enc := ini.NewEncoder(&buf)
+	enc.Dialect = d
+	if err := enc.Encode(doc); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if diff := cmp.Diff(input, buf.String()); diff != "" {
+		t.Errorf("Encode output (-want, +got):\n%s", diff)
+	}
+
+	// Comments without indentation or a marker are made to fit the block.
+	buf.Reset()
+	h := enc.Handler()
+	loc := ini.Location{ValueComments: []ini.ValueComment{{Offset: 1, Text: "note"}, {Offset: 9, Text: "; last"}}}
+	if err := h.KeyValue(loc, "k", []string{"a\nb"}); err != nil {
+		t.Fatalf("KeyValue failed: %v", err)
+	}
+	if got, want := buf.String(), "k = a\n    ; note\n    b\n    ; last\n"; got != want {
+		t.Errorf("KeyValue: got %q, want %q", got, want)
+	}
+}
+
+func TestEncoderBlockCommentsDefault(t *testing.T) {
+	const input = "k = a\n  ; c\n  b\n  ; d\n  e\nj = 1\n"
+	const want = "k = a\n  ; c\nk = b\n  ; d\nk = e\nj = 1\n"
+	doc, err := ini.Load(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	var buf strings.Builder
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteTo output (-want, +got):\n%s", diff)
+	}
+	again, err := ini.Load(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Load output failed: %v", err)
+	}
+	got, orig := again.Section("").Key("k"), doc.Section("").Key("k")
+	if diff := cmp.Diff(orig.Values, got.Values); diff != "" {
+		t.Errorf("Round trip values (-input, +output):\n%s", diff)
+	}
+	if diff := cmp.Diff(orig.ValueComments, got.ValueComments); diff != "" {
+		t.Errorf("Round trip comments (-input, +output):\n%s", diff)
+	}
+}
+
+func TestEncoderValues(t *testing.T) {
+	var buf strings.Builder
+	enc := ini.NewEncoder(&buf)
+	enc.Comment("no marker")
+	enc.KeyValue("empty", nil)
+	enc.KeyValue("multi", []string{"a", "b"})
+	enc.Dialect.ListValues = true
+	enc.KeyValue("list", []string{"a", "b", "c"})
+	if err := enc.KeyValue("bad", []string{"a,b"}); err == nil {
+		t.Error("KeyValue: got nil error for a list value with a comma")
+	}
+	const want = `; no marker
+empty =
+multi = a
+multi = b
+list = a, b, c
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Output (-want, +got):\n%s", diff)
+	}
+
+	doc := &ini.Document{Sections: []*ini.Section{
+		{Name: "s", Keys: []*ini.Key{{Name: "k", Values: []string{"v"}}}},
+	}}
+	buf.Reset()
+	n, err := doc.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if got, want := buf.String(), "[s]\nk = v\n"; got != want {
+		t.Errorf("WriteTo: got %q, want %q", got, want)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo: got n=%d, want %d", n, buf.Len())
+	}
+}
+
+func TestEncoderNumberedKeys(t *testing.T) {
+	const input = "path.0 = /bin\npath.1 = /usr/bin\nsha1 = x\nx1 = a\nx2 = b\n"
+	const want = "path.1 = /bin\npath.2 = /usr/bin\nsha1 = x\nx.1 = a\nx.2 = b\n"
+	var buf strings.Builder
+	enc := ini.NewEncoder(&buf)
+	enc.NumberedKeys = true
+	if err := ini.Parse(strings.NewReader(input), enc.Handler()); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Output (-want, +got):\n%s", diff)
+	}
+
+	collect := func(s string) map[string]map[string][]string {
+		m := make(map[string]map[string][]string)
+		h := ini.CollectMap(m)
+		h.NumberedKeys = true
+		if err := ini.Parse(strings.NewReader(s), h); err != nil {
+			t.Fatalf("Parse %q failed: %v", s, err)
+		}
+		return m
+	}
+	if diff := cmp.Diff(collect(input), collect(buf.String())); diff != "" {
+		t.Errorf("Round trip (-input, +output):\n%s", diff)
+	}
+}
+
+// collectKeys parses s with d, and returns its keys and values by section.
+func collectKeys(t *testing.T, d ini.Dialect, s string) map[string]map[string][]string {
+	t.Helper()
+	m := make(map[string]map[string][]string)
+	h := ini.CollectMap(m)
+	h.Dialect = d
+	if err := ini.Parse(strings.NewReader(s), h); err != nil {
+		t.Fatalf("Parse %q failed: %v", s, err)
+	}
+	return m
+}
+
+func TestEncoderRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		d     ini.Dialect
+		input string
+	}{
+		{"Default", ini.Dialect{}, "a = 1\n; note\n[s]\nk = x\n  y\nk = z\nempty\n"},
+		{"InnoSetup", ini.InnoSetup, "[Setup]\nAppName = \"  My App  \"\nMsg = 50%% done%nnext\nArg = %1 file\nQ = \"\"x\"\"\n"},
+		{"Flake8", ini.Flake8, "# about\n[flake8]\nignore =\n  E1,\n  W2\nmax-line-length = 100\n"},
+		{"Pacman", ini.Pacman, "# about\n[options]\nHoldPkg = pacman glibc\nColor\n[core]\nServer = https://x/$repo\n"},
+		{"Samba", ini.Samba, "[share]\npath = C:\\dir\\\n  sub\nComment = x\npublic = yes\n"},
+		{"PHP", ini.PHP, "[db]\nhost = \"local;host\"\nflag = yes\nq = \"say \\\"hi\\\" \\\\\"\nsemi = a ; comment\npad = \"  p \"\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := test.d.Load(strings.NewReader(test.input))
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			var buf strings.Builder
+			enc := ini.NewEncoder(&buf)
+			enc.Dialect = test.d
+			if err := enc.Encode(doc); err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+			want := collectKeys(t, test.d, test.input)
+			if diff := cmp.Diff(want, collectKeys(t, test.d, buf.String())); diff != "" {
+				t.Errorf("Round trip of %q (-input, +output):\n%s", buf.String(), diff)
+			}
+		})
+	}
+}
+
+func TestEncoderEscapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		d       ini.Dialect
+		section string
+		values  []string
+	}{
+		{"InnoSetup", ini.InnoSetup, "s", []string{"  pad  ", "50%", "%n", "a\nb", `"quoted"`, ""}},
+		{"PHP", ini.PHP, "s", []string{"a;b", "yes", "Null", `"q"`, `'q'`, `back\slash"`, " pad", "plain"}},
+		{"Quoted", ini.Dialect{QuotedValues: true, BraceBlocks: true}, "s", []string{"{", " x"}},
+		{"Brackets", ini.Dialect{EscapedBrackets: true}, `a[1]\b`, []string{"v"}},
+		{"Samba", ini.Samba, "s", []string{`a\b`, "x"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf strings.Builder
+			enc := ini.NewEncoder(&buf)
+			enc.Dialect = test.d
+			if err := enc.Section(test.section); err != nil {
+				t.Fatalf("Section failed: %v", err)
+			}
+			want := map[string][]string{}
+			for i, v := range test.values {
+				key := fmt.Sprintf("k%d", i)
+				if err := enc.KeyValue(key, []string{v}); err != nil {
+					t.Fatalf("KeyValue(%q) failed: %v", v, err)
+				}
+				want[key] = []string{v}
+			}
+			got := collectKeys(t, test.d, buf.String())
+			if diff := cmp.Diff(map[string]map[string][]string{test.section: want}, got); diff != "" {
+				t.Errorf("Parse of %q (-want, +got):\n%s", buf.String(), diff)
+			}
+		})
+	}
+}
+
+func TestEncoderSectionEnd(t *testing.T) {
+	const input = "[s]\nk = 1\n[/s]\ntop = 2\n"
+	d := ini.Dialect{SectionEnd: true}
+	var buf strings.Builder
+	enc := ini.NewEncoder(&buf)
+	enc.Dialect = d
+	if err := ini.Parse(strings.NewReader(input), enc.Handler()); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got, want := buf.String(), "[s]\nk = 1\n\n[end]\ntop = 2\n"; got != want {
+		t.Errorf("Output: got %q, want %q", got, want)
+	}
+	if diff := cmp.Diff(collectKeys(t, d, input), collectKeys(t, d, buf.String())); diff != "" {
+		t.Errorf("Round trip (-input, +output):\n%s", diff)
+	}
+}
+
+func TestEncoderErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		d    ini.Dialect
+		run  func(*ini.Encoder) error
+	}{
+		{"empty section", ini.Dialect{}, func(e *ini.Encoder) error { return e.Section("") }},
+		{"bracket", ini.Dialect{}, func(e *ini.Encoder) error { return e.Section("a]b") }},
+		{"spaced section", ini.Dialect{}, func(e *ini.Encoder) error { return e.Section(" a") }},
+		{"end marker", ini.Dialect{SectionEnd: true}, func(e *ini.Encoder) error { return e.Section("end") }},
+		{"empty key", ini.Dialect{}, func(e *ini.Encoder) error { return e.KeyValue("", nil) }},
+		{"equals", ini.Dialect{}, func(e *ini.Encoder) error { return e.KeyValue("x=y", nil) }},
+		{"comment key", ini.Dialect{}, func(e *ini.Encoder) error { return e.KeyValue("; k", nil) }},
+		{"hash key", ini.Flake8, func(e *ini.Encoder) error { return e.KeyValue("#k", nil) }},
+		{"header key", ini.Dialect{}, func(e *ini.Encoder) error { return e.KeyValue("[k", nil) }},
+		{"newline key", ini.Dialect{}, func(e *ini.Encoder) error { return e.KeyValue("a\nb", nil) }},
+		{"backslash", ini.Samba, func(e *ini.Encoder) error { return e.KeyValue("path", []string{`C:\dir\`}) }},
+		{"backslash comment", ini.Samba, func(e *ini.Encoder) error { return e.Comment(`C:\dir\`) }},
+		{"block", ini.Dialect{BraceBlocks: true}, func(e *ini.Encoder) error { return e.KeyValue("k", []string{"{"}) }},
+		{"comment line", ini.Dialect{MultilineValues: true}, func(e *ini.Encoder) error {
+			return e.KeyValue("k", []string{"a\n; b"})
+		}},
+	}
+	for _, test := range tests {
+		var buf strings.Builder
+		enc := ini.NewEncoder(&buf)
+		enc.Dialect = test.d
+		if err := test.run(enc); err == nil {
+			t.Errorf("%s: got nil error, output %q", test.name, buf.String())
+		} else if buf.Len() != 0 {
+			t.Errorf("%s: got output %q with error %v", test.name, buf.String(), err)
+		}
+	}
+}
+
+func TestEncoderAdjacentKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		d     ini.Dialect
+		input string
+	}{
+		{"Value then bare", ini.Dialect{}, "k = 1\nk\n"},
+		{"Bare then value", ini.Dialect{}, "k\nk = 1\n"},
+		{"Bare keys", ini.Dialect{}, "\xff\n\xff\nx\n"},
+		{"Three", ini.Dialect{}, "k\nk\nk = 2\nk\n"},
+		{"Folded", ini.Samba, "[s]\nPath = x\npath\n"},
+		{"List", ini.Flake8, "[s]\nk = a, b\nk\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := test.d.Load(strings.NewReader(test.input))
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			var buf strings.Builder
+			enc := ini.NewEncoder(&buf)
+			enc.Dialect = test.d
+			if err := enc.Encode(doc); err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+			again, err := test.d.Load(strings.NewReader(buf.String()))
+			if err != nil {
+				t.Fatalf("Load output failed: %v", err)
+			}
+			opt := cmpopts.IgnoreFields(ini.Location{}, "Line")
+			if diff := cmp.Diff(doc, again, opt); diff != "" {
+				t.Errorf("Round trip of %q (-input, +output):\n%s", buf.String(), diff)
+			}
+		})
+	}
+
+	// Keys with values that would merge cannot be written.
+	var buf strings.Builder
+	enc := ini.NewEncoder(&buf)
+	enc.KeyValue("k", []string{"1"})
+	if err := enc.KeyValue("k", []string{"2"}); err == nil {
+		t.Errorf("KeyValue: got nil error for a key that merges, output %q", buf.String())
+	}
+	enc.Dialect = ini.Pacman
+	if err := enc.KeyValue("Include", []string{"/etc/x"}); err == nil {
+		t.Error("KeyValue: got nil error for the include key")
+	}
+}
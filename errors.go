@@ -0,0 +1,108 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// An ErrorList is an error that reports a collection of errors, such as the
+// problems found by Schema.ValidateAll.
+type ErrorList struct {
+	Errors []error // the errors collected, in order
+
+	// If Max > 0, it is the maximum number of errors to collect. Further
+	// errors are discarded, and Truncated is set to true.
+	Max       int
+	Truncated bool
+}
+
+// Add adds err to the list. If the list already holds Max errors, err is
+// discarded and e.Truncated is set to true.
+func (e *ErrorList) Add(err error) {
+	if e.Max > 0 && len(e.Errors) >= e.Max {
+		e.Truncated = true
+		return
+	}
+	e.Errors = append(e.Errors, err)
+}
+
+// Err returns e if it contains any errors, or otherwise nil.
+func (e *ErrorList) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Len, Less, and Swap implement sort.Interface, ordering errors by the file
+// and line of their locations. Errors without a location are ordered first.
+func (e *ErrorList) Len() int      { return len(e.Errors) }
+func (e *ErrorList) Swap(i, j int) { e.Errors[i], e.Errors[j] = e.Errors[j], e.Errors[i] }
+func (e *ErrorList) Less(i, j int) bool {
+	a, b := ErrorLocation(e.Errors[i]), ErrorLocation(e.Errors[j])
+	if a.File != b.File {
+		return a.File < b.File
+	}
+	return a.Line < b.Line
+}
+
+// Sort sorts the errors of e by location, preserving the order of errors at
+// the same location.
+func (e *ErrorList) Sort() { sort.Stable(e) }
+
+func (e *ErrorList) Error() string {
+	if len(e.Errors) == 0 {
+		return "no errors"
+	}
+	switch n := len(e.Errors) - 1; {
+	case e.Truncated:
+		return fmt.Sprintf("%v (and more than %d more errors)", e.Errors[0], n)
+	case n > 0:
+		return fmt.Sprintf("%v (and %d more errors)", e.Errors[0], n)
+	}
+	return e.Errors[0].Error()
+}
+
+// Unwrap returns the errors of e, so that errors.Is and errors.As examine
+// each of them.
+func (e *ErrorList) Unwrap() []error { return e.Errors }
+
+// ErrorLocation returns the location reported by err, if err or an error it
+// wraps is a *SyntaxError, *ValidationError, *HandlerError, *CallbackError,
+// or *IOError, checking the types in that order. Otherwise it returns a zero
+// Location.
+func ErrorLocation(err error) Location {
+	var serr *SyntaxError
+	var verr *ValidationError
+	var herr *HandlerError
+	var cerr *CallbackError
+	var ioerr *IOError
+	switch {
+	case errors.As(err, &serr):
+		return serr.Location
+	case errors.As(err, &verr):
+		return verr.Location
+	case errors.As(err, &herr):
+		return herr.Location
+	case errors.As(err, &cerr):
+		return cerr.Location
+	case errors.As(err, &ioerr):
+		return ioerr.Location
+	}
+	return Location{}
+}
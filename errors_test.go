@@ -0,0 +1,82 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/creachadair/ini"
+)
+
+func TestErrorList(t *testing.T) {
+	var errs ini.ErrorList
+	if err := errs.Err(); err != nil {
+		t.Errorf("Err of empty list: got %v, want nil", err)
+	}
+
+	errOther := errors.New("other")
+	errs.Add(&ini.SyntaxError{Location: ini.Location{Line: 5}, Desc: "five"})
+	errs.Add(&ini.HandlerError{Location: ini.Location{Line: 2}, Value: "two"})
+	errs.Add(errOther)
+	errs.Add(&ini.ValidationError{Location: ini.Location{Line: 2}, Desc: "also two"})
+	errs.Add(&ini.SyntaxError{Location: ini.Location{Line: 1, File: "b"}, Desc: "b1"})
+	errs.Sort()
+
+	var got []string
+	for _, err := range errs.Errors {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		"other",
+		"line 2: handler panicked: two",
+		"line 2: also two",
+		"line 5: five",
+		"b: line 1: b1",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Errors: got %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Error %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	err := errs.Err()
+	if got, want := err.Error(), "other (and 4 more errors)"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+	if !errors.Is(err, errOther) || !errors.Is(err, ini.ErrSyntax) || !errors.Is(err, ini.ErrValidation) {
+		t.Errorf("Error %v does not match its contents", err)
+	}
+	if loc := ini.ErrorLocation(fmt.Errorf("wrapped: %w", errs.Errors[3])); loc.Line != 5 {
+		t.Errorf("ErrorLocation of wrapped error: got %+v, want line 5", loc)
+	}
+	if loc := ini.ErrorLocation(errOther); loc.Line != 0 {
+		t.Errorf("ErrorLocation of other error: got %+v, want zero", loc)
+	}
+
+	capped := ini.ErrorList{Max: 1}
+	capped.Add(errOther)
+	capped.Add(errOther)
+	if !capped.Truncated || len(capped.Errors) != 1 {
+		t.Errorf("Capped list: got %+v, want 1 error and truncated", capped)
+	}
+	if got, want := capped.Error(), "other (and more than 0 more errors)"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// An Event records a single callback from the parser, in a form suitable for
+// serialization.
+type Event struct {
+	Kind    string   `json:"kind"`              // one of the Kind constants
+	Line    int      `json:"line"`              // as in Location
+	Section string   `json:"section,omitempty"` // as in Location
+	File    string   `json:"file,omitempty"`    // as in Location
+	Tag     string   `json:"tag,omitempty"`     // as in Location
+	Raw     string   `json:"raw,omitempty"`     // as in Location
+	Name    string   `json:"name,omitempty"`    // section name or key
+	Text    string   `json:"text,omitempty"`    // comment text
+	Values  []string `json:"values,omitempty"`  // key values
+
+	Comments []string `json:"comments,omitempty"` // as in Location
+	Indents  []string `json:"indents,omitempty"`  // as in Location
+
+	ValueComments []ValueComment `json:"value_comments,omitempty"` // as in Location
+
+	Captures map[string]string `json:"captures,omitempty"` // as in Location
+
+	SectionOrdinal int `json:"section_ordinal,omitempty"` // as in Location
+	KeyOrdinal     int `json:"key_ordinal,omitempty"`     // as in Location
+}
+
+// Constants defining the kinds of events.
+const (
+	KindComment = "comment" // a Comment callback
+	KindSection = "section" // a Section callback
+	KindKey     = "key"     // a KeyValue callback
+)
+
+// Location returns the location of the event.
+func (e Event) Location() Location {
+	return Location{
+		Line: e.Line, Section: e.Section, File: e.File, Tag: e.Tag,
+		Raw: e.Raw, Comments: e.Comments, Indents: e.Indents,
+		Captures: e.Captures, SectionOrdinal: e.SectionOrdinal, KeyOrdinal: e.KeyOrdinal,
+		ValueComments: e.ValueComments,
+	}
+}
+
+// EventHandler returns a Handler that invokes f with an Event for each
+// callback from the parser. If f reports an error, parsing stops.
+func EventHandler(f func(Event) error) Handler {
+	return Handler{
+		Comment: func(loc Location, text string) error {
+			return f(Event{
+				Kind: KindComment, Line: loc.Line, Section: loc.Section, File: loc.File, Tag: loc.Tag,
+				Text: text,
+			})
+		},
+		Section: func(loc Location, name string) error {
+			return f(Event{
+				Kind: KindSection, Line: loc.Line, Section: loc.Section, File: loc.File, Tag: loc.Tag,
+				Raw: loc.Raw, Name: name, Comments: loc.Comments, Captures: loc.Captures,
+				SectionOrdinal: loc.SectionOrdinal,
+			})
+		},
+		KeyValue: func(loc Location, key string, values []string) error {
+			return f(Event{
+				Kind: KindKey, Line: loc.Line, Section: loc.Section, File: loc.File, Tag: loc.Tag,
+				Name: key, Values: values, Comments: loc.Comments, Indents: loc.Indents,
+				SectionOrdinal: loc.SectionOrdinal, KeyOrdinal: loc.KeyOrdinal,
+				ValueComments: loc.ValueComments,
+			})
+		},
+	}
+}
+
+// JSONHandler returns a Handler that writes each callback from the parser to
+// w as an Event encoded in JSON, followed by a newline. The resulting stream
+// has one JSON object per line (ndjson). If writing to w fails, parsing stops
+// and the write error is reported.
+func JSONHandler(w io.Writer) Handler {
+	enc := json.NewEncoder(w)
+	return EventHandler(func(e Event) error { return enc.Encode(e) })
+}
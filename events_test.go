@@ -0,0 +1,38 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJSONHandler(t *testing.T) {
+	const input = "; head\n[s]\nk = a\n  b\n"
+	var buf strings.Builder
+	if err := ini.Parse(strings.NewReader(input), ini.JSONHandler(&buf)); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	const want = `{"kind":"comment","line":1,"text":"; head"}
+{"kind":"section","line":2,"name":"s"}
+{"kind":"key","line":3,"section":"s","name":"k","values":["a","b"]}
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("JSONHandler output (-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// FileRefPrefix is the prefix of a value that refers to the contents of a
+// file, for ResolveFileRefs.
+const FileRefPrefix = "@file:"
+
+// ResolveFileRefs returns a Handler that replaces each value of the form
+// "@file:path" with the contents of the named file in fsys, and delivers the
+// result to h. Comments, section headers, and the options of h are passed
+// through unchanged. For example, with fsys = os.DirFS("/etc/myapp"):
+//
+//	[tls]
+//	cert = @file:certs/server.pem
+//
+// delivers "cert" with the contents of /etc/myapp/certs/server.pem. Paths
+// use the syntax of fs.ValidPath, so a reference cannot name a file outside
+// fsys. To resolve references in some other way, provide an fs.FS that does
+// so.
+//
+// If maxSize > 0, a file larger than maxSize bytes is reported as a
+// *ValidationError. An error opening or reading a file is reported as an
+// *IOError wrapping it. Both have the location of the key.
+func ResolveFileRefs(fsys fs.FS, maxSize int64, h Handler) Handler {
+	kv := h.KeyValue
+	h.KeyValue = func(loc Location, key string, values []string) error {
+		var out []string
+		for i, v := range values {
+			name, ok := strings.CutPrefix(v, FileRefPrefix)
+			if !ok {
+				continue
+			}
+			data, err := readFileRef(fsys, name, maxSize)
+			if err != nil {
+				if err == errFileTooLarge {
+					return &ValidationError{Location: loc, Desc: "referenced file too large", Key: name}
+				}
+				return &IOError{Location: loc, Err: err}
+			}
+			if out == nil {
+				out = append([]string(nil), values...)
+			}
+			out[i] = string(data)
+		}
+		if kv == nil {
+			return nil
+		} else if out == nil {
+			out = values
+		}
+		return kv(loc, key, out)
+	}
+	return h
+}
+
+// errFileTooLarge is reported by readFileRef for a file exceeding its limit.
+var errFileTooLarge = errors.New("file too large")
+
+// readFileRef reads the contents of name from fsys, reporting errFileTooLarge
+// if maxSize > 0 and the file is larger than maxSize bytes.
+func readFileRef(fsys fs.FS, name string, maxSize int64) ([]byte, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if maxSize > 0 {
+		r = io.LimitReader(f, maxSize+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	} else if maxSize > 0 && int64(len(data)) > maxSize {
+		return nil, errFileTooLarge
+	}
+	return data, nil
+}
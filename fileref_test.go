@@ -0,0 +1,74 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResolveFileRefs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"certs/server.pem": {Data: []byte("-----BEGIN CERTIFICATE-----\nxyz\n")},
+		"big.bin":          {Data: []byte(strings.Repeat("x", 100))},
+	}
+	const input = `[tls]
+cert = @file:certs/server.pem
+name = plain
+list = a
+  @file:certs/server.pem
+email = user@file:example.com
+`
+	m := make(map[string]map[string][]string)
+	if err := ini.Parse(strings.NewReader(input), ini.ResolveFileRefs(fsys, 64, ini.CollectMap(m))); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	pem := "-----BEGIN CERTIFICATE-----\nxyz\n"
+	want := map[string]map[string][]string{
+		"tls": {
+			"cert":  {pem},
+			"name":  {"plain"},
+			"list":  {"a", pem},
+			"email": {"user@file:example.com"},
+		},
+	}
+	if diff := cmp.Diff(want, m); diff != "" {
+		t.Errorf("ResolveFileRefs (-want, +got)\n%s", diff)
+	}
+
+	parse := func(input string) error {
+		return ini.Parse(strings.NewReader(input), ini.ResolveFileRefs(fsys, 64, ini.Handler{}))
+	}
+	if err := parse("x = @file:big.bin\n"); err == nil {
+		t.Error("Large file: got nil error")
+	} else if e, ok := err.(*ini.ValidationError); !ok || e.Key != "big.bin" || errors.Is(err, ini.ErrSyntax) {
+		t.Errorf("Large file: got %v, want validation error for big.bin", err)
+	}
+	err := parse("\nx = @file:missing\n")
+	if !errors.Is(err, fs.ErrNotExist) || !errors.Is(err, ini.ErrIO) {
+		t.Errorf("Missing file: got %v, want %v and %v", err, fs.ErrNotExist, ini.ErrIO)
+	} else if loc := ini.ErrorLocation(err); loc.Line != 2 {
+		t.Errorf("Missing file: got location %+v, want line 2", loc)
+	}
+	if err := parse("x = @file:../etc/passwd\n"); err == nil {
+		t.Error("Path outside fsys: got nil error")
+	}
+}
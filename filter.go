@@ -0,0 +1,33 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import "strings"
+
+// StripPrefix returns a Handler that delivers to h only the keys whose names
+// begin with prefix, with the prefix removed. Keys that do not have the
+// prefix, or that consist only of the prefix, are skipped. Comments and
+// section headers are passed through unchanged, as are the options of h.
+func StripPrefix(prefix string, h Handler) Handler {
+	kv := h.KeyValue
+	h.KeyValue = func(loc Location, key string, values []string) error {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok || rest == "" || kv == nil {
+			return nil
+		}
+		return kv(loc, rest, values)
+	}
+	return h
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStripPrefix(t *testing.T) {
+	var out []result
+	h := ini.StripPrefix("app.", recordTo(&out, ini.Handler{}))
+	if err := ini.Parse(strings.NewReader(`; shared
+[server]
+app.port = 80
+other.port = 81
+app. = x
+app.name = a
+  b
+[client]
+application = y
+`), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "comment", "", nil},
+		{2, "section", "server", nil},
+		{3, "key/value", "port", []string{"80"}},
+		{6, "key/value", "name", []string{"a", "b"}},
+		{8, "section", "client", nil},
+	}
+	if diff := cmp.Diff(want, out); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	msgGitBadCount  = "invalid GIT_CONFIG_COUNT"
+	msgGitNoKey     = "missing config key"
+	msgGitNoValue   = "missing config value"
+	msgGitBadQuote  = "invalid quoting in GIT_CONFIG_PARAMETERS"
+	msgGitNoSection = "key does not contain a section"
+)
+
+// ParseGitEnv parses git configuration settings from the environment
+// variables in env, which has the format returned by os.Environ, and invokes
+// the callbacks on h with the results.
+//
+// Settings are read first from GIT_CONFIG_COUNT and the corresponding
+// GIT_CONFIG_KEY_n and GIT_CONFIG_VALUE_n variables, then from the quoted
+// list in GIT_CONFIG_PARAMETERS, in the same order git itself applies them.
+// Other variables in env are ignored.
+//
+// A git key like "remote.origin.url" is delivered as a key "url" in section
+// "remote.origin": The section name is everything before the last dot in the
+// key. A Section callback is made whenever the section changes from that of
+// the previous setting, so the events match those of parsing an INI file
+// with one section header per run of keys. A setting without a value (for
+// example "core.bare" in GIT_CONFIG_PARAMETERS) is reported with the single
+// value "", as for a bare key in a file.
+//
+// Key names are checked if h.Dialect.StrictKeys is true; other options of the
+// Dialect do not apply.
+//
+// Since settings in the environment do not have line numbers, the Line field
+// of each Location gives the 1-based ordinal position of the setting.
+func ParseGitEnv(env []string, h Handler) error {
+	vars := make(map[string]string)
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			vars[k] = v
+		}
+	}
+
+	loc := Location{Tag: h.Tag}
+	emit := func(key string, values []string) error {
+		loc.Line++
+		i := strings.LastIndex(key, ".")
+		if i <= 0 || i == len(key)-1 {
+			return syntaxError(loc, msgGitNoSection, key)
+		}
+		if err := h.Dialect.checkKey(loc, key[i+1:]); err != nil {
+			return err
+		}
+		if sec := key[:i]; sec != loc.Section {
+			if err := h.section(loc, sec); err != nil {
+				return err
+			}
+			loc.Section = sec
+		}
+		return h.keyValue(loc, key[i+1:], values)
+	}
+
+	if s, ok := vars["GIT_CONFIG_COUNT"]; ok && s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return syntaxError(loc, msgGitBadCount, s)
+		}
+		for i := 0; i < n; i++ {
+			ks := "GIT_CONFIG_KEY_" + strconv.Itoa(i)
+			vs := "GIT_CONFIG_VALUE_" + strconv.Itoa(i)
+			key, ok := vars[ks]
+			if !ok || key == "" {
+				return syntaxError(Location{Line: loc.Line + 1, Tag: h.Tag}, msgGitNoKey, ks)
+			}
+			val, ok := vars[vs]
+			if !ok {
+				return syntaxError(Location{Line: loc.Line + 1, Tag: h.Tag}, msgGitNoValue, vs)
+			}
+			if err := emit(key, []string{val}); err != nil {
+				return err
+			}
+		}
+	}
+
+	rest := vars["GIT_CONFIG_PARAMETERS"]
+	for {
+		rest = strings.TrimLeft(rest, " \t\n")
+		if rest == "" {
+			return nil
+		}
+		key, tail, ok := sqDequote(rest)
+		if !ok {
+			return syntaxError(Location{Line: loc.Line + 1, Tag: h.Tag}, msgGitBadQuote, rest)
+		}
+		var values []string
+		if strings.HasPrefix(tail, "=") {
+			// New style: 'key'='value'
+			val, next, ok := sqDequote(tail[1:])
+			if !ok {
+				return syntaxError(Location{Line: loc.Line + 1, Tag: h.Tag}, msgGitBadQuote, tail)
+			}
+			values, tail = []string{val}, next
+		} else if k, v, ok := strings.Cut(key, "="); ok {
+			// Old style: 'key=value'
+			key, values = k, []string{v}
+		} else {
+			values = []string{""}
+		}
+		if tail != "" && !strings.ContainsAny(tail[:1], " \t\n") {
+			return syntaxError(Location{Line: loc.Line + 1, Tag: h.Tag}, msgGitBadQuote, tail)
+		}
+		if err := emit(key, values); err != nil {
+			return err
+		}
+		rest = tail
+	}
+}
+
+// sqDequote removes one single-quoted word from the front of s, using the
+// quoting rules of git's sq_quote: The word is enclosed in single quotes, and
+// a literal quote or exclamation mark X is written as a quoted backslash
+// escape, closing and reopening the quote around \X.  It returns the unquoted
+// word and the remaining unconsumed input.
+func sqDequote(s string) (word, rest string, ok bool) {
+	if !strings.HasPrefix(s, "'") {
+		return "", s, false
+	}
+	var sb strings.Builder
+	s = s[1:]
+	for {
+		i := strings.IndexByte(s, '\'')
+		if i < 0 {
+			return "", s, false // unterminated quote
+		}
+		sb.WriteString(s[:i])
+		s = s[i+1:]
+		if len(s) >= 3 && s[0] == '\\' && (s[1] == '\'' || s[1] == '!') && s[2] == '\'' {
+			sb.WriteByte(s[1])
+			s = s[3:]
+			continue
+		}
+		return sb.String(), s, true
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func runGitEnv(env []string) ([]result, error) {
+	var got []result
+	err := ini.ParseGitEnv(env, recordTo(&got, ini.Handler{}))
+	return got, err
+}
+
+func TestParseGitEnv(t *testing.T) {
+	tests := []struct {
+		desc string
+		env  []string
+		want []result
+	}{
+		{"empty", nil, nil},
+		{"unrelated", []string{"HOME=/home/user", "GIT_DIR=.git"}, nil},
+
+		{"count", []string{
+			"GIT_CONFIG_COUNT=3",
+			"GIT_CONFIG_KEY_0=core.editor", "GIT_CONFIG_VALUE_0=vi",
+			"GIT_CONFIG_KEY_1=core.pager", "GIT_CONFIG_VALUE_1=",
+			"GIT_CONFIG_KEY_2=remote.origin.url", "GIT_CONFIG_VALUE_2=a=b",
+		}, []result{
+			{1, "section", "core", nil},
+			{1, "key/value", "editor", []string{"vi"}},
+			{2, "key/value", "pager", []string{""}},
+			{3, "section", "remote.origin", nil},
+			{3, "key/value", "url", []string{"a=b"}},
+		}},
+
+		{"parameters", []string{
+			`GIT_CONFIG_PARAMETERS='core.editor=vi' 'core.bare' 'user.name'='O'\''Hara'`,
+		}, []result{
+			{1, "section", "core", nil},
+			{1, "key/value", "editor", []string{"vi"}},
+			{2, "key/value", "bare", []string{""}},
+			{3, "section", "user", nil},
+			{3, "key/value", "name", []string{"O'Hara"}},
+		}},
+
+		{"count before parameters", []string{
+			"GIT_CONFIG_PARAMETERS='a.x=2'",
+			"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=a.x", "GIT_CONFIG_VALUE_0=1",
+		}, []result{
+			{1, "section", "a", nil},
+			{1, "key/value", "x", []string{"1"}},
+			{2, "key/value", "x", []string{"2"}},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := runGitEnv(test.env)
+			if err != nil {
+				t.Fatalf("ParseGitEnv failed: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ParseGitEnv results (-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseGitEnvErrors(t *testing.T) {
+	tests := [][]string{
+		{"GIT_CONFIG_COUNT=x"},
+		{"GIT_CONFIG_COUNT=1"},
+		{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=a.b"},
+		{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=nosection", "GIT_CONFIG_VALUE_0=1"},
+		{"GIT_CONFIG_PARAMETERS=unquoted"},
+		{"GIT_CONFIG_PARAMETERS='a.b"},
+		{"GIT_CONFIG_PARAMETERS='a.b'='c"},
+		{"GIT_CONFIG_PARAMETERS='a.b'x"},
+	}
+	for _, env := range tests {
+		got, err := runGitEnv(env)
+		t.Logf("ParseGitEnv(%q) reports %v", env, err)
+		if _, ok := err.(*ini.SyntaxError); !ok {
+			t.Errorf("ParseGitEnv(%q): got %+v, %v, want syntax error", env, got, err)
+		}
+	}
+}
+
+func TestParseGitEnvStrictKeys(t *testing.T) {
+	env := []string{"GIT_CONFIG_COUNT=2",
+		"GIT_CONFIG_KEY_0=a.ok", "GIT_CONFIG_VALUE_0=1",
+		"GIT_CONFIG_KEY_1=a.b=c", "GIT_CONFIG_VALUE_1=2",
+	}
+	if err := ini.ParseGitEnv(env, ini.Handler{}); err != nil {
+		t.Errorf("ParseGitEnv: unexpected error: %v", err)
+	}
+	h := ini.Handler{Dialect: ini.Dialect{StrictKeys: true}}
+	err := ini.ParseGitEnv(env, h)
+	if e, ok := err.(*ini.SyntaxError); !ok || e.Line != 2 || e.Key != `"b=c"` {
+		t.Errorf("ParseGitEnv: got %v, want invalid key at line 2", err)
+	}
+}
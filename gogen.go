@@ -0,0 +1,86 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// WriteGo parses the INI data from r and writes to w the source of a Go file
+// in package pkg that declares a variable with the given name holding its
+// contents, in the form collected by CollectMap:
+//
+//	var name = map[string]map[string][]string{ ... }
+//
+// This allows default settings to be compiled into a program without parsing
+// them at run time. The generated file does not import this package, and so
+// it does not use a Document: the map does not record the order of sections
+// and keys, or any comments. A program that needs those can embed the INI
+// text instead, for example with a go:embed directive, and Load it. Errors
+// from parsing r are returned, and nothing is written. It is an error if pkg
+// or name is not a valid Go identifier.
+func WriteGo(w io.Writer, r io.Reader, pkg, name string) error {
+	if !token.IsIdentifier(pkg) {
+		return fmt.Errorf("invalid package name %q", pkg)
+	} else if !token.IsIdentifier(name) {
+		return fmt.Errorf("invalid variable name %q", name)
+	}
+	m := make(map[string]map[string][]string)
+	if err := Parse(r, CollectMap(m)); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by ini.WriteGo. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	fmt.Fprintf(&buf, "var %s = map[string]map[string][]string{\n", name)
+	for _, sec := range sortedKeys(m) {
+		fmt.Fprintf(&buf, "%s: {\n", strconv.Quote(sec))
+		for _, key := range sortedKeys(m[sec]) {
+			fmt.Fprintf(&buf, "%s: {", strconv.Quote(key))
+			for i, v := range m[sec][key] {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				buf.WriteString(strconv.Quote(v))
+			}
+			buf.WriteString("},\n")
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// sortedKeys returns the keys of m in lexicographic order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
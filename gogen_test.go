@@ -0,0 +1,68 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteGo(t *testing.T) {
+	const input = `top = "quoted"
+[server]
+port = 80
+hosts = a
+  b
+[empty]
+`
+	var buf strings.Builder
+	if err := ini.WriteGo(&buf, strings.NewReader(input), "config", "Defaults"); err != nil {
+		t.Fatalf("WriteGo failed: %v", err)
+	}
+	const want = `// Code generated by ini.WriteGo. DO NOT EDIT.
+
+package config
+
+var Defaults = map[string]map[string][]string{
+	"": {
+		"top": {"\"quoted\""},
+	},
+	"empty": {},
+	"server": {
+		"hosts": {"a", "b"},
+		"port":  {"80"},
+	},
+}
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteGo (-want, +got)\n%s", diff)
+	}
+
+	for _, test := range []struct{ input, pkg, name string }{
+		{"[bad", "config", "Defaults"},
+		{"", "bad-pkg", "Defaults"},
+		{"", "config", "1var"},
+	} {
+		buf.Reset()
+		if err := ini.WriteGo(&buf, strings.NewReader(test.input), test.pkg, test.name); err == nil {
+			t.Errorf("WriteGo(%q, %q, %q): got nil error", test.input, test.pkg, test.name)
+		} else if buf.Len() != 0 {
+			t.Errorf("WriteGo(%q, %q, %q) wrote output despite an error", test.input, test.pkg, test.name)
+		}
+	}
+}
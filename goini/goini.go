@@ -0,0 +1,301 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package goini provides a subset of the API of the gopkg.in/ini.v1 package,
+// backed by the ini parser, to ease migration for programs that use it.
+//
+// Only reading is supported. The grammar is that of the ini package with
+// HashComments enabled, so some inputs accepted by gopkg.in/ini.v1 are
+// reported as syntax errors; errors carry the location of the problem.
+//
+// As in gopkg.in/ini.v1, keys that occur before any section header belong to
+// the section named by DefaultSection, and looking up a section or key that
+// does not exist yields an empty value rather than nil.
+package goini
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/creachadair/ini"
+)
+
+// DefaultSection is the name of the section holding keys that occur before
+// any section header.
+const DefaultSection = "DEFAULT"
+
+// Dialect is the INI dialect used to parse input.
+var Dialect = ini.Dialect{HashComments: true}
+
+// A File is the parsed contents of one or more configuration sources.
+type File struct {
+	sections []*Section
+}
+
+// Load parses the given sources in order and merges them into a single File.
+// Each source must be a file name (string), the contents of a file ([]byte),
+// or an io.Reader. Keys in later sources replace keys of the same name in
+// earlier ones.
+func Load(source interface{}, others ...interface{}) (*File, error) {
+	f := new(File)
+	for _, src := range append([]interface{}{source}, others...) {
+		if err := f.load(src); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Empty returns a new empty File.
+func Empty() *File { return new(File) }
+
+func (f *File) load(src interface{}) error {
+	var r io.Reader
+	var name string
+	switch t := src.(type) {
+	case string:
+		in, err := os.Open(t)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		r, name = in, t
+	case []byte:
+		r = bytes.NewReader(t)
+	case io.Reader:
+		r = t
+	default:
+		return fmt.Errorf("goini: unsupported source type %T", src)
+	}
+	cur := f.Section(DefaultSection)
+	err := ini.Parse(r, ini.Handler{
+		Dialect: Dialect,
+		Section: func(_ ini.Location, name string) error {
+			cur = f.Section(name)
+			return nil
+		},
+		KeyValue: func(_ ini.Location, key string, values []string) error {
+			cur.set(key, ini.JoinValues(values, ini.JoinNewline))
+			return nil
+		},
+	})
+	if se, ok := err.(*ini.SyntaxError); ok && name != "" {
+		se.File = name
+	}
+	return err
+}
+
+// Section returns the section with the given name, creating an empty one if
+// it does not exist. An empty name denotes DefaultSection.
+func (f *File) Section(name string) *Section {
+	if s := f.find(name); s != nil {
+		return s
+	}
+	if name == "" {
+		name = DefaultSection
+	}
+	s := &Section{name: name}
+	f.sections = append(f.sections, s)
+	return s
+}
+
+// GetSection returns the section with the given name, or an error if it does
+// not exist. An empty name denotes DefaultSection.
+func (f *File) GetSection(name string) (*Section, error) {
+	if s := f.find(name); s != nil {
+		return s, nil
+	}
+	return nil, fmt.Errorf("section %q does not exist", name)
+}
+
+// HasSection reports whether f has a section with the given name.
+func (f *File) HasSection(name string) bool { return f.find(name) != nil }
+
+// Sections returns the sections of f, in order of first occurrence.
+func (f *File) Sections() []*Section { return f.sections }
+
+// SectionStrings returns the names of the sections of f, in order of first
+// occurrence.
+func (f *File) SectionStrings() []string {
+	names := make([]string, len(f.sections))
+	for i, s := range f.sections {
+		names[i] = s.name
+	}
+	return names
+}
+
+func (f *File) find(name string) *Section {
+	if name == "" {
+		name = DefaultSection
+	}
+	for _, s := range f.sections {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// A Section is a named collection of keys.
+type Section struct {
+	name string
+	keys []*Key
+}
+
+// Name returns the name of the section.
+func (s *Section) Name() string { return s.name }
+
+// Key returns the key with the given name. If the key does not exist, Key
+// returns an empty key that is not added to s.
+func (s *Section) Key(name string) *Key {
+	if k := s.find(name); k != nil {
+		return k
+	}
+	return &Key{name: name}
+}
+
+// GetKey returns the key with the given name, or an error if it does not
+// exist.
+func (s *Section) GetKey(name string) (*Key, error) {
+	if k := s.find(name); k != nil {
+		return k, nil
+	}
+	return nil, fmt.Errorf("key %q does not exist", name)
+}
+
+// HasKey reports whether s has a key with the given name.
+func (s *Section) HasKey(name string) bool { return s.find(name) != nil }
+
+// Keys returns the keys of s, in order of first occurrence.
+func (s *Section) Keys() []*Key { return s.keys }
+
+// KeyStrings returns the names of the keys of s, in order of first
+// occurrence.
+func (s *Section) KeyStrings() []string {
+	names := make([]string, len(s.keys))
+	for i, k := range s.keys {
+		names[i] = k.name
+	}
+	return names
+}
+
+// KeysHash returns a map from key names to values.
+func (s *Section) KeysHash() map[string]string {
+	m := make(map[string]string, len(s.keys))
+	for _, k := range s.keys {
+		m[k.name] = k.value
+	}
+	return m
+}
+
+func (s *Section) find(name string) *Key {
+	for _, k := range s.keys {
+		if k.name == name {
+			return k
+		}
+	}
+	return nil
+}
+
+func (s *Section) set(name, value string) {
+	if k := s.find(name); k != nil {
+		k.value = value
+	} else {
+		s.keys = append(s.keys, &Key{name: name, value: value})
+	}
+}
+
+// A Key is a key and its value. The lines of a value spanning multiple lines
+// are joined with newlines.
+type Key struct {
+	name, value string
+}
+
+// Name returns the name of the key.
+func (k *Key) Name() string { return k.name }
+
+// Value returns the value of the key.
+func (k *Key) Value() string { return k.value }
+
+// String returns the value of the key.
+func (k *Key) String() string { return k.value }
+
+// MustString returns the value of the key, or def if the value is empty.
+func (k *Key) MustString(def string) string {
+	if k.value == "" {
+		return def
+	}
+	return k.value
+}
+
+// Strings splits the value of the key at each occurrence of delim, trims
+// surrounding whitespace from each piece, and discards empty pieces.
+func (k *Key) Strings(delim string) []string {
+	var out []string
+	for _, s := range strings.Split(k.value, delim) {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Bool parses the value of the key as a Boolean, as ini.ParseBool.
+func (k *Key) Bool() (bool, error) { return ini.ParseBool(k.value) }
+
+// MustBool returns the Boolean value of the key, or def if it is not valid.
+func (k *Key) MustBool(def bool) bool {
+	if v, err := k.Bool(); err == nil {
+		return v
+	}
+	return def
+}
+
+// Int parses the value of the key as a decimal integer.
+func (k *Key) Int() (int, error) { return strconv.Atoi(k.value) }
+
+// MustInt returns the integer value of the key, or def if it is not valid.
+func (k *Key) MustInt(def int) int {
+	if v, err := k.Int(); err == nil {
+		return v
+	}
+	return def
+}
+
+// Int64 parses the value of the key as a decimal integer.
+func (k *Key) Int64() (int64, error) { return strconv.ParseInt(k.value, 10, 64) }
+
+// MustInt64 returns the integer value of the key, or def if it is not valid.
+func (k *Key) MustInt64(def int64) int64 {
+	if v, err := k.Int64(); err == nil {
+		return v
+	}
+	return def
+}
+
+// Float64 parses the value of the key as a floating-point number.
+func (k *Key) Float64() (float64, error) { return strconv.ParseFloat(k.value, 64) }
+
+// MustFloat64 returns the floating-point value of the key, or def if it is
+// not valid.
+func (k *Key) MustFloat64(def float64) float64 {
+	if v, err := k.Float64(); err == nil {
+		return v
+	}
+	return def
+}
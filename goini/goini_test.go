@@ -0,0 +1,115 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goini_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/creachadair/ini/goini"
+	"github.com/google/go-cmp/cmp"
+)
+
+const input = `app_mode = development
+
+[paths]
+# Path to where grafana can store temp files
+data = /home/git/grafana
+
+[server]
+protocol = http
+http_port = 9999
+enforce_domain = true
+ratio = 0.5
+hosts = a, b,, c
+motd = hello
+  world
+`
+
+func TestLoad(t *testing.T) {
+	f, err := goini.Load(strings.NewReader(input), []byte("[server]\nhttp_port = 8080\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"DEFAULT", "paths", "server"}, f.SectionStrings()); diff != "" {
+		t.Errorf("SectionStrings (-want, +got)\n%s", diff)
+	}
+	if got := f.Section("").Key("app_mode").String(); got != "development" {
+		t.Errorf("app_mode: got %q, want development", got)
+	}
+	if got := f.Section("paths").Key("data").String(); got != "/home/git/grafana" {
+		t.Errorf("data: got %q, want /home/git/grafana", got)
+	}
+
+	s := f.Section("server")
+	if diff := cmp.Diff([]string{"protocol", "http_port", "enforce_domain", "ratio", "hosts", "motd"}, s.KeyStrings()); diff != "" {
+		t.Errorf("KeyStrings (-want, +got)\n%s", diff)
+	}
+	if got := s.Key("http_port").MustInt(0); got != 8080 {
+		t.Errorf("http_port: got %d, want 8080", got)
+	}
+	if got := s.Key("enforce_domain").MustBool(false); !got {
+		t.Error("enforce_domain: got false, want true")
+	}
+	if got := s.Key("ratio").MustFloat64(0); got != 0.5 {
+		t.Errorf("ratio: got %v, want 0.5", got)
+	}
+	if got := s.Key("protocol").MustInt64(-1); got != -1 {
+		t.Errorf("protocol: got %d, want -1", got)
+	}
+	if diff := cmp.Diff([]string{"a", "b", "c"}, s.Key("hosts").Strings(",")); diff != "" {
+		t.Errorf("hosts (-want, +got)\n%s", diff)
+	}
+	if got := s.Key("motd").Value(); got != "hello\nworld" {
+		t.Errorf("motd: got %q, want %q", got, "hello\nworld")
+	}
+
+	// Missing sections and keys are empty, not nil.
+	if got := f.Section("nonesuch").Key("x").MustString("def"); got != "def" {
+		t.Errorf("missing key: got %q, want def", got)
+	}
+	if s.HasKey("x") {
+		t.Error("HasKey(x) is true after lookup")
+	}
+	if _, err := s.GetKey("x"); err == nil {
+		t.Error("GetKey(x): got nil error")
+	}
+	if _, err := f.GetSection("other"); err == nil {
+		t.Error("GetSection(other): got nil error")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.ini")
+	if err := os.WriteFile(path, []byte("[ok]\n[broken\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := goini.Load(path)
+	var se *ini.SyntaxError
+	if !errors.As(err, &se) {
+		t.Fatalf("Load: got %v, want *ini.SyntaxError", err)
+	}
+	if se.File != path || se.Line != 2 {
+		t.Errorf("Load: got error at %s:%d, want %s:2", se.File, se.Line, path)
+	}
+
+	if _, err := goini.Load(42); err == nil {
+		t.Error("Load(42): got nil error")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxIncludeDepth is the maximum nesting depth of included files.
+const maxIncludeDepth = 16
+
+// include parses the files matching pattern, the value of the include key at
+// loc, and invokes the callbacks on h with the results. A relative pattern in
+// an included file is resolved against the directory of that file. It returns
+// the name of the section in effect at the end of the last file.
+func include(loc Location, pattern string, h Handler, cfg parseConfig) (string, error) {
+	if cfg.depth >= maxIncludeDepth {
+		return "", syntaxError(loc, msgIncludeDepth, pattern)
+	}
+	if loc.File != "" && pattern != "" && !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(filepath.Dir(loc.File), pattern)
+	}
+	paths, err := filepath.Glob(pattern)
+	if err != nil || pattern == "" {
+		return "", syntaxError(loc, msgInvalidInclude, pattern)
+	} else if len(paths) == 0 && !strings.ContainsAny(pattern, "*?[") {
+		paths = []string{pattern} // report the missing file
+	}
+
+	h.Progress = nil // report progress only for the main input
+	section := loc.Section
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil && h.Recover != nil {
+			// Report the failure as a syntax error, so that it can be recovered
+			// and the remaining files included.
+			if err := h.Recover(&SyntaxError{Location: loc, Desc: msgIncludeOpen, Key: path}); err != nil {
+				return "", err
+			}
+			continue
+		} else if err != nil {
+			return "", &IOError{Location: loc, Err: err}
+		}
+		err = parse(f, h, parseConfig{
+			keep:  cfg.keep,
+			start: Location{Section: section, File: path},
+			depth: cfg.depth + 1,
+			cont:  cfg.cont,
+			line:  cfg.line,
+			last:  &section,
+			names: cfg.names,
+			ord:   cfg.ord,
+		})
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return section, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPacmanInclude(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite := func(name, data string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	mirrors := mustWrite("mirrorlist", "# mirrors\nServer = https://a.example/$repo\n")
+	mustWrite("b.repo", "[extra]\nInclude = "+mirrors+"\n")
+	mustWrite("a.repo", "SigLevel = Never\n")
+
+	input := `[options]
+HoldPkg = pacman
+[core]
+Include = ` + mirrors + `
+Include = ` + filepath.Join(dir, "*.repo") + `
+Include = ` + filepath.Join(dir, "*.none") + `
+Usage = Sync
+`
+	var got []ini.Event
+	h := ini.EventHandler(func(e ini.Event) error {
+		got = append(got, e)
+		return nil
+	})
+	h.Dialect = ini.Pacman
+	if err := ini.Parse(strings.NewReader(input), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	aRepo, bRepo := filepath.Join(dir, "a.repo"), filepath.Join(dir, "b.repo")
+	want := []ini.Event{
+		{Kind: ini.KindSection, Line: 1, Name: "options"},
+		{Kind: ini.KindKey, Line: 2, Section: "options", Name: "HoldPkg", Values: []string{"pacman"}},
+		{Kind: ini.KindSection, Line: 3, Section: "options", Name: "core"},
+		{Kind: ini.KindComment, Line: 1, Section: "core", File: mirrors, Text: "# mirrors"},
+		{Kind: ini.KindKey, Line: 2, Section: "core", File: mirrors, Name: "Server",
+			Values: []string{"https://a.example/$repo"}},
+		{Kind: ini.KindKey, Line: 1, Section: "core", File: aRepo, Name: "SigLevel", Values: []string{"Never"}},
+		{Kind: ini.KindSection, Line: 1, Section: "core", File: bRepo, Name: "extra"},
+		{Kind: ini.KindComment, Line: 1, Section: "extra", File: mirrors, Text: "# mirrors"},
+		{Kind: ini.KindKey, Line: 2, Section: "extra", File: mirrors, Name: "Server",
+			Values: []string{"https://a.example/$repo"}},
+		{Kind: ini.KindKey, Line: 7, Section: "extra", Name: "Usage", Values: []string{"Sync"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+
+	loop := mustWrite("loop", "Include = "+filepath.Join(dir, "loop")+"\n")
+	h = ini.Handler{Dialect: ini.Pacman}
+	if err := ini.Parse(strings.NewReader("Include = "+loop), h); err == nil {
+		t.Error("Parse include loop: got nil, want error")
+	} else if !errors.Is(err, ini.ErrSyntax) {
+		t.Errorf("Parse include loop: got %v, want syntax error", err)
+	}
+	if err := ini.Parse(strings.NewReader("Include = "+filepath.Join(dir, "missing")), h); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Parse missing include: got %v, want %v", err, fs.ErrNotExist)
+	}
+	if err := ini.Parse(strings.NewReader("Include = ["), h); !errors.Is(err, ini.ErrSyntax) {
+		t.Errorf("Parse bad pattern: got %v, want syntax error", err)
+	}
+}
+
+func TestIncludeRelative(t *testing.T) {
+	// A relative include in an included file is resolved against its own
+	// directory, not the working directory.
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "conf.d"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"outer.conf":        "Include = conf.d/*.conf\n",
+		"conf.d/a.conf":     "a = 1\nInclude = ../leaf.conf\n",
+		"leaf.conf":         "leaf = 2\n",
+		"conf.d/ignore.txt": "ignored = 3\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := make(map[string]map[string][]string)
+	h := ini.CollectMap(m)
+	h.Dialect = ini.Pacman
+	input := "Include = " + filepath.Join(dir, "outer.conf") + "\n"
+	if err := ini.Parse(strings.NewReader(input), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := map[string]map[string][]string{"": {"a": {"1"}, "leaf": {"2"}}}
+	if diff := cmp.Diff(want, m); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// An IndexEntry records the position of a section header in INI data.
+type IndexEntry struct {
+	Section string // the normalized section name
+	Line    int    // the line number of the header, 1-based
+	Offset  int64  // the byte offset of the start of the header line
+}
+
+// BuildIndex scans the INI data from r and returns the positions of all its
+// section headers, in order of occurrence. Only section headers are checked
+// for errors; use Parse to check the remainder of the input.
+func BuildIndex(r io.Reader) ([]IndexEntry, error) {
+	var out []IndexEntry
+	var loc Location
+	buf, pos := newLineScanner(r, 0, 0)
+	for buf.Scan() {
+		loc.Line++
+		clean := strings.TrimSpace(buf.Text())
+		if clean == "" || clean[0] != '[' {
+			continue
+		}
+		name, err := Dialect{}.parseHeader(loc, clean)
+		if err != nil {
+			return nil, err
+		}
+		start, _ := pos()
+		out = append(out, IndexEntry{Section: name, Line: loc.Line, Offset: start})
+		loc.Section = name
+	}
+	if err := buf.Err(); err != nil {
+		return nil, &IOError{Location: Location{Line: loc.Line + 1}, Err: err}
+	}
+	return out, nil
+}
+
+// ParseSectionAt parses the single section described by e from the INI data
+// in ra, and invokes the callbacks on h with the results, as Parse. Parsing
+// begins with the section header at e.Offset and ends at the next section
+// header or the end of the input. Line numbers are reported relative to the
+// start of ra, but the Section field of the Location for the header is "".
+func ParseSectionAt(ra io.ReaderAt, e IndexEntry, h Handler) error {
+	r := io.NewSectionReader(ra, e.Offset, math.MaxInt64-e.Offset)
+	return parse(r, h, parseConfig{
+		start: Location{Line: e.Line - 1},
+		one:   true,
+	})
+}
+
+// ParseRange parses the INI data in the byte range [start, end) of ra, and
+// invokes the callbacks on h with the results, as Parse. The line at start
+// is numbered line, and later lines are numbered from it, so that line
+// numbers are relative to the start of ra when line is correct. The Section
+// field of locations before the first section header in the range is "".
+// Typically start and line come from an IndexEntry built by BuildIndex, and
+// end is the offset of a later entry; only the range itself is read.
+func ParseRange(ra io.ReaderAt, start, end int64, line int, h Handler) error {
+	if start < 0 || end < start {
+		return fmt.Errorf("invalid range [%d, %d)", start, end)
+	} else if line < 1 {
+		return fmt.Errorf("invalid line number %d", line)
+	}
+	return parse(io.NewSectionReader(ra, start, end-start), h, parseConfig{
+		start: Location{Line: line - 1},
+	})
+}
+
+// newLineScanner returns a scanner that splits r into lines. The pos function
+// reports the byte offsets in r of the start of the most recent line and of
+// the end of its line terminator. If size > 0, it is the initial size of the
+// scanner's buffer, and if max > 0, it is the maximum length of a line;
+// otherwise the defaults of bufio.Scanner are used.
+func newLineScanner(r io.Reader, size, max int) (_ *bufio.Scanner, pos func() (start, end int64)) {
+	var start, next int64
+	buf := bufio.NewScanner(r)
+	if size > 0 || max > 0 {
+		if size <= 0 {
+			size = defaultBufferSize
+		}
+		if max <= 0 {
+			max = bufio.MaxScanTokenSize
+		}
+		buf.Buffer(make([]byte, 0, size), max)
+	}
+	buf.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		adv, tok, err := bufio.ScanLines(data, atEOF)
+		if tok != nil {
+			start, next = next, next+int64(adv)
+		}
+		return adv, tok, err
+	})
+	return buf, func() (int64, int64) { return start, next }
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+const indexInput = "top = 1\r\n[alpha]\r\na = 2\n\n  [ beta ]\nb = 3\n  4\n; end\n[gamma]\n"
+
+func TestBuildIndex(t *testing.T) {
+	got, err := ini.BuildIndex(strings.NewReader(indexInput))
+	if err != nil {
+		t.Fatalf("BuildIndex failed: %v", err)
+	}
+	want := []ini.IndexEntry{
+		{Section: "alpha", Line: 2, Offset: 9},
+		{Section: "beta", Line: 5, Offset: 25},
+		{Section: "gamma", Line: 9, Offset: 52},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("BuildIndex (-want, +got)\n%s", diff)
+	}
+
+	if _, err := ini.BuildIndex(strings.NewReader("a\n[bad\n")); err == nil {
+		t.Error("BuildIndex: got nil, want error")
+	}
+}
+
+func TestParseSectionAt(t *testing.T) {
+	idx, err := ini.BuildIndex(strings.NewReader(indexInput))
+	if err != nil {
+		t.Fatalf("BuildIndex failed: %v", err)
+	}
+	tests := []struct {
+		entry ini.IndexEntry
+		want  []result
+	}{
+		{idx[0], []result{
+			{2, "section", "alpha", nil},
+			{3, "key/value", "a", []string{"2"}},
+		}},
+		{idx[1], []result{
+			{5, "section", "beta", nil},
+			{6, "key/value", "b", []string{"3", "4"}},
+			{8, "comment", "", nil},
+		}},
+		{idx[2], []result{
+			{9, "section", "gamma", nil},
+		}},
+	}
+	ra := strings.NewReader(indexInput)
+	for _, test := range tests {
+		var got []result
+		if err := ini.ParseSectionAt(ra, test.entry, recordTo(&got, ini.Handler{})); err != nil {
+			t.Errorf("ParseSectionAt(%+v) failed: %v", test.entry, err)
+		} else if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseSectionAt(%+v) (-want, +got)\n%s", test.entry, diff)
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	idx, err := ini.BuildIndex(strings.NewReader(indexInput))
+	if err != nil {
+		t.Fatalf("BuildIndex failed: %v", err)
+	}
+	ra := strings.NewReader(indexInput)
+	tests := []struct {
+		start, end int64
+		line       int
+		want       []result
+	}{
+		{0, idx[0].Offset, 1, []result{{1, "key/value", "top", []string{"1"}}}},
+		{idx[0].Offset, idx[2].Offset, idx[0].Line, []result{
+			{2, "section", "alpha", nil},
+			{3, "key/value", "a", []string{"2"}},
+			{5, "section", "beta", nil},
+			{6, "key/value", "b", []string{"3", "4"}},
+			{8, "comment", "", nil},
+		}},
+		{idx[1].Offset + 17, int64(len(indexInput)), 7, []result{
+			{7, "key/value", "4", []string{""}},
+			{8, "comment", "", nil},
+			{9, "section", "gamma", nil},
+		}},
+		{idx[2].Offset, idx[2].Offset, idx[2].Line, nil},
+	}
+	for _, test := range tests {
+		var got []result
+		if err := ini.ParseRange(ra, test.start, test.end, test.line, recordTo(&got, ini.Handler{})); err != nil {
+			t.Errorf("ParseRange(%d, %d) failed: %v", test.start, test.end, err)
+		} else if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseRange(%d, %d) (-want, +got)\n%s", test.start, test.end, diff)
+		}
+	}
+
+	if err := ini.ParseRange(ra, 5, 2, 1, ini.Handler{}); err == nil {
+		t.Error("ParseRange(5, 2): got nil, want error")
+	}
+	if err := ini.ParseRange(ra, 0, 2, 0, ini.Handler{}); err == nil {
+		t.Error("ParseRange(line 0): got nil, want error")
+	}
+}
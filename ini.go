@@ -16,10 +16,14 @@
 package ini
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"path"
+	"strconv"
 	"strings"
+	"sync"
+	"unicode/utf8"
 )
 
 // Handler is a structure containing options and callbacks used by the parser
@@ -33,40 +37,266 @@ type Handler struct {
 
 	// Section delivers a section header. Whitespace in name is normalized.  The
 	// loc.Section field contains the name of the most recent section label
-	// prior to this one.
+	// prior to this one. The name is "" only for the end of a section or
+	// block, when the Dialect permits them.
 	Section func(loc Location, name string) error
 
 	// KeyValue delivers the values for a single key. Whitespace in the key name
 	// is normalized. The values slice will not be empty, but will contain ""
-	// for a key with only one empty value.
+	// for a key with only one empty value. Backslashes in values are not
+	// interpreted, so Windows paths like C:\Program Files\App are delivered
+	// unchanged.
 	KeyValue func(loc Location, key string, values []string) error
+
+	// If NumberedKeys is true, a run of consecutive keys following the
+	// numbered-key convention is delivered as a single key with multiple
+	// values. A numbered key is a base name followed by a decimal index, either
+	// directly ("key1") or after a dot ("key.1"). A run begins with index 0 or
+	// 1, and continues while each subsequent key has the same base and the next
+	// consecutive index. A run has at least two keys, so a lone key whose
+	// name happens to end in 0 or 1, such as "sha1", is not renamed. For
+	// example:
+	//
+	//	path.0 = /usr/bin
+	//	path.1 = /usr/local/bin
+	//
+	// is delivered as a key "path" with values "/usr/bin" and "/usr/local/bin",
+	// at the location of "path.0". Keys that are not part of a run are
+	// delivered with their names unchanged.
+	NumberedKeys bool
+
+	// If AttachComments is true, comments are not delivered to the Comment
+	// callback as they occur. Instead, the comments preceding each section
+	// header or key are delivered in the Comments field of its Location.
+	// Comments at the end of the input, which precede no section or key, are
+	// still delivered to the Comment callback.
+	AttachComments bool
+
+	// If RawHeaders is true, the Location for each Section callback has the
+	// text of the header line in its Raw field, including any whitespace
+	// around the brackets.
+	RawHeaders bool
+
+	// If ValueIndents is true, the Location for each KeyValue callback has
+	// the indentation of the line each value came from in its Indents field,
+	// so that the layout of continuation lines can be reproduced. Values
+	// split from a line by ListValues have the indentation of that line, and
+	// the lines joined to a value by MultilineValues are part of the value of
+	// the key line.
+	ValueIndents bool
+
+	// BlockComments controls the delivery of comments inside the indented
+	// block of a multi-line value: the lines joined by MultilineValues, or
+	// the indented lines that give further values of a key by default. By
+	// default, they are delivered as other comments are, and in the default
+	// dialect such a comment ends the values of the key.
+	BlockComments BlockCommentMode
+
+	// If ReuseValues is true, the parser reuses the storage of the values
+	// slice passed to KeyValue after the callback returns, so a callback that
+	// retains the values must copy the slice. This reduces allocation when
+	// parsing inputs with many multi-valued keys. The storage is drawn from
+	// a pool shared by all parsers; a slice whose capacity has grown beyond
+	// MaxReuseCap elements is released rather than kept in the pool. If
+	// MaxReuseCap <= 0, a default of 1024 is used.
+	ReuseValues bool
+	MaxReuseCap int
+
+	// If InternNames is true, the parser keeps a table of the key and section
+	// names it has delivered, and delivers identical names using the same
+	// string. This saves memory when the names are retained from a large
+	// input with few distinct names, such as a machine-generated file. The
+	// table is shared with included files, but not between calls to Parse.
+	InternNames bool
+
+	// BufferSize and MaxLineSize control the buffer used to read the input.
+	// If BufferSize > 0, it is the initial size of the buffer in bytes. If
+	// MaxLineSize > 0, it is the maximum length in bytes of a line of input,
+	// including its line terminator; otherwise the maximum is 64KiB. A longer
+	// line is reported as the error bufio.ErrTooLong.
+	BufferSize  int
+	MaxLineSize int
+
+	// If Ordinals is true, the Location for each Section and KeyValue
+	// callback reports the positions of the element in its SectionOrdinal and
+	// KeyOrdinal fields, so that order-sensitive consumers need not count
+	// the callbacks themselves.
+	Ordinals bool
+
+	// If Tag is not empty, it is reported in the Tag field of every Location
+	// delivered to the callbacks or reported in an error, so that elements
+	// from many inputs parsed into one destination can be told apart.
+	Tag string
+
+	// Dialect selects optional extensions to the INI syntax.
+	Dialect Dialect
+
+	// If Progress is not nil, it is called after every ProgressLines lines of
+	// the input, and once more at the end of the input, with the number of
+	// bytes and lines read so far. If ProgressLines <= 0, a default of 1000 is
+	// used. If Progress reports an error, parsing stops and that error is
+	// returned. Progress is not called for the contents of included files.
+	Progress      func(bytes int64, lines int) error
+	ProgressLines int
+
+	// If a callback panics, parsing stops and the panic is reported as an
+	// error of concrete type *HandlerError. If PropagatePanics is true, the
+	// panic is not recovered, and unwinds through the caller of Parse.
+	PropagatePanics bool
+
+	// If Recover is not nil, syntax errors in the input do not stop parsing.
+	// Instead, each is passed to Recover, and the parser skips the damaged
+	// input and resumes at the next line that begins a key or section. After
+	// an invalid section header, the keys that follow it are skipped up to
+	// the next section header, since their section is unknown. If Recover
+	// reports an error, parsing stops and that error is returned. In this
+	// mode, a line that is not valid UTF-8 is also reported as a syntax
+	// error and skipped. A brace block left open at a section header or the
+	// end of the input is reported, and the open blocks are closed. An
+	// include that fails, including one whose file cannot be opened, is
+	// reported and skipped. Errors reported by callbacks or by the reader are
+	// not recovered.
+	Recover func(*SyntaxError) error
+
+	// If ContinueOnError is true, an error reported by the Comment, Section,
+	// or KeyValue callback does not stop parsing. Instead, each such error is
+	// recorded as a *CallbackError with the location of the element, and if
+	// any were recorded, Parse reports them at the end of the input as an
+	// *ErrorList, followed by the error that stopped parsing, if any.
+	ContinueOnError bool
 }
 
-func (h Handler) comment(loc Location, text string) error {
+// continueOnError returns a copy of h whose callbacks add the errors they
+// report to errs as *CallbackError values, and report nil.
+func (h Handler) continueOnError(errs *ErrorList) Handler {
+	h.ContinueOnError = false
+	record := func(loc Location, err error) error {
+		if err != nil {
+			errs.Add(&CallbackError{Location: loc, Err: err})
+		}
+		return nil
+	}
+	if f := h.Comment; f != nil {
+		h.Comment = func(loc Location, text string) error { return record(loc, f(loc, text)) }
+	}
+	if f := h.Section; f != nil {
+		h.Section = func(loc Location, name string) error { return record(loc, f(loc, name)) }
+	}
+	if f := h.KeyValue; f != nil {
+		h.KeyValue = func(loc Location, key string, values []string) error {
+			return record(loc, f(loc, key, values))
+		}
+	}
+	return h
+}
+
+func (h Handler) comment(loc Location, text string) (err error) {
 	if h.Comment != nil {
+		defer h.recoverAt(loc, &err)
 		return h.Comment(loc, text)
 	}
 	return nil
 }
 
-func (h Handler) section(loc Location, name string) error {
+func (h Handler) section(loc Location, name string) (err error) {
 	if h.Section != nil {
+		defer h.recoverAt(loc, &err)
 		return h.Section(loc, name)
 	}
 	return nil
 }
 
-func (h Handler) keyValue(loc Location, key string, values []string) error {
+func (h Handler) keyValue(loc Location, key string, values []string) (err error) {
 	if h.KeyValue != nil {
+		defer h.recoverAt(loc, &err)
 		return h.KeyValue(loc, key, values)
 	}
 	return nil
 }
 
+// recoverAt recovers a panic from a callback for the element at loc, and
+// reports it as a *HandlerError in *err, unless h.PropagatePanics is true.
+// It must be called directly by defer.
+func (h Handler) recoverAt(loc Location, err *error) {
+	if h.PropagatePanics {
+		return
+	}
+	if v := recover(); v != nil {
+		*err = &HandlerError{Location: loc, Value: v}
+	}
+}
+
 // A Location describes the physical location of an input element.
 type Location struct {
 	Line    int    // line number, 1-based
 	Section string // most recent section name (or "")
+	File    string // the included file containing the element (or "")
+	Tag     string // the Tag of the Handler (or "")
+
+	// If Handler.RawHeaders is true, Raw holds the text of the line for a
+	// section header, as it appears in the input.
+	Raw string
+
+	// If Handler.AttachComments is true, Comments holds the text of the
+	// comments preceding the element, in order of occurrence.
+	Comments []string
+
+	// If the Dialect has a HeaderPattern that matches the name of a section
+	// header, Captures holds the text captured by its named groups.
+	Captures map[string]string
+
+	// If Handler.Ordinals is true, SectionOrdinal is the 1-based position of
+	// the section header among all the section headers of the input, or 0
+	// for keys that precede the first header. For a key, it is the ordinal
+	// of the header of the section containing the key. KeyOrdinal is the
+	// 1-based position of a key among the keys following the same header.
+	// Section headers skipped by ParseSections and its variants are counted,
+	// as are the block boundaries delivered as sections under BraceBlocks.
+	SectionOrdinal int
+	KeyOrdinal     int
+
+	// If Handler.ValueIndents is true, Indents holds the indentation of the
+	// line each value of a key came from, in parallel with its values. The
+	// entry for a value given on a key line is "", and the entry for a value
+	// from a continuation line is the whitespace that precedes it.
+	Indents []string
+
+	// If Handler.BlockComments is BlockCommentsAttach, ValueComments holds
+	// the comments inside the indented block of a multi-line value, in
+	// order of occurrence.
+	ValueComments []ValueComment
+}
+
+// A BlockCommentMode selects how the comments inside the indented block of a
+// multi-line value are delivered.
+type BlockCommentMode int
+
+// Constants defining the modes for block comments.
+const (
+	// Deliver comments in the block as other comments are, before the key
+	// whose value contains them.
+	BlockCommentsDeliver BlockCommentMode = iota
+
+	// Deliver comments in the block with the key whose value contains them,
+	// in the ValueComments field of its Location. With this mode, the
+	// comments can be written back in place; see Encoder.
+	BlockCommentsAttach
+
+	// Discard comments in the block.
+	BlockCommentsIgnore
+)
+
+// A ValueComment is a comment inside the indented block of a multi-line
+// value.
+type ValueComment struct {
+	// Offset is the number of lines of the value that precede the comment,
+	// counting the key line and the blank lines before the comment. Without
+	// MultilineValues, where each indented line is a separate value, it is
+	// the number of values that precede the comment.
+	Offset int `json:"offset"`
+
+	// Text is the text of the comment line, as for the Comment callback.
+	Text string `json:"text"`
 }
 
 // SyntaxError is the concrete type of error values denoting syntax problems
@@ -79,12 +309,88 @@ type SyntaxError struct {
 
 func (s *SyntaxError) Error() string {
 	msg := fmt.Sprintf("line %d: %s", s.Location.Line, s.Desc)
+	if s.Location.File != "" {
+		msg = s.Location.File + ": " + msg
+	}
 	if s.Key != "" {
 		msg += ": " + s.Key
 	}
 	return msg
 }
 
+// HandlerError is the concrete type of error values reporting a panic in a
+// Handler callback.
+type HandlerError struct {
+	Location             // the location of the element being delivered
+	Value    interface{} // the value passed to panic
+}
+
+func (e *HandlerError) Error() string {
+	msg := fmt.Sprintf("line %d: handler panicked: %v", e.Location.Line, e.Value)
+	if e.Location.File != "" {
+		msg = e.Location.File + ": " + msg
+	}
+	return msg
+}
+
+// Unwrap returns the panic value if it is an error, or otherwise nil.
+func (e *HandlerError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// CallbackError is the concrete type of errors reported by Handler callbacks
+// and recorded because Handler.ContinueOnError is true.
+type CallbackError struct {
+	Location       // the location of the element being delivered
+	Err      error // the error reported by the callback
+}
+
+func (e *CallbackError) Error() string {
+	msg := fmt.Sprintf("line %d: %v", e.Location.Line, e.Err)
+	if e.Location.File != "" {
+		msg = e.Location.File + ": " + msg
+	}
+	return msg
+}
+
+// Unwrap returns the error reported by the callback.
+func (e *CallbackError) Unwrap() error { return e.Err }
+
+// IOError is the concrete type of error values reporting a failure to read
+// the input, or to open an included file.
+type IOError struct {
+	Location       // the line that could not be read, or the include key
+	Err      error // the error reported by the reader
+}
+
+func (e *IOError) Error() string {
+	msg := fmt.Sprintf("line %d: read error: %v", e.Location.Line, e.Err)
+	if e.Location.File != "" {
+		msg = e.Location.File + ": " + msg
+	}
+	return msg
+}
+
+// Unwrap returns the error reported by the reader.
+func (e *IOError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrIO, so that errors.Is(err, ErrIO) reports
+// true for any *IOError.
+func (e *IOError) Is(target error) bool { return target == ErrIO }
+
+// Is reports whether target is ErrSyntax, so that errors.Is(err, ErrSyntax)
+// reports true for any *SyntaxError.
+func (s *SyntaxError) Is(target error) bool { return target == ErrSyntax }
+
+// Sentinel errors for classifying failures with errors.Is. An include of a
+// file that does not exist matches both ErrIO and fs.ErrNotExist.
+var (
+	ErrSyntax     = errors.New("syntax error")     // matches *SyntaxError
+	ErrValidation = errors.New("validation error") // matches *ValidationError
+	ErrIO         = errors.New("I/O error")        // matches *IOError
+)
+
 func syntaxError(loc Location, msg, key string) error {
 	return &SyntaxError{Location: loc, Desc: msg, Key: key}
 }
@@ -93,6 +399,14 @@ const (
 	msgUnclosedHeader = "unclosed section header"
 	msgInvalidSection = "invalid section name"
 	msgEmptyKey       = "empty key"
+	msgMismatchedEnd  = "mismatched section end"
+	msgUnclosedBlock  = "unclosed block"
+	msgUnmatchedBlock = "unmatched block end"
+	msgInvalidInclude = "invalid include pattern"
+	msgIncludeDepth   = "includes nested too deeply"
+	msgIncludeOpen    = "cannot open included file"
+	msgInvalidKey     = "invalid key name"
+	msgInvalidUTF8    = "invalid UTF-8"
 )
 
 // Parse scans the INI data from r and invokes the callbacks on h with the
@@ -148,66 +462,512 @@ const (
 //
 // Parse does not check for duplication among section headers or keys; the
 // caller is responsible for any validation that is required.
-// Line continuations with trailing backslashes are not currently supported.
+// Line continuations with trailing backslashes are not supported, except as
+// enabled by the Dialect.
 // String quotation is not currently supported.
-func Parse(r io.Reader, h Handler) error {
-	buf := bufio.NewScanner(r)
-	var loc Location // current physical input location
+//
+// Whitespace is any character for which unicode.IsSpace reports true, for the
+// purposes of trimming and normalizing names, but only a space or tab at the
+// start of a line indents it; see Dialect.ASCIISpace and UniformSpace.
+//
+// The Dialect field of h may be used to enable optional extensions to this
+// syntax; see Dialect.
+func Parse(r io.Reader, h Handler) error { return parse(r, h, parseConfig{}) }
+
+// ParseSections behaves as Parse, but invokes the callbacks on h only for the
+// contents of the sections whose names are listed in names. Include "" in
+// names to select the keys and comments before the first section header.
+//
+// The contents of other sections are skipped without accumulating values, so
+// syntax errors within them, other than in section headers, are not reported.
+func ParseSections(r io.Reader, names []string, h Handler) error {
+	keep := make(map[string]bool)
+	for _, name := range names {
+		keep[name] = true
+	}
+	return parse(r, h, parseConfig{
+		keep: func(name string) bool { return keep[name] },
+	})
+}
+
+// ParseMatching behaves as ParseSections, but invokes the callbacks on h only
+// for the contents of the sections whose names match the glob pattern, using
+// the syntax of path.Match. For example, the pattern "profile *" selects the
+// profile sections of an AWS configuration file. An error is reported without
+// reading r if pattern is malformed.
+func ParseMatching(r io.Reader, pattern string, h Handler) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return err
+	}
+	return parse(r, h, parseConfig{
+		keep: func(name string) bool {
+			ok, _ := path.Match(pattern, name)
+			return ok
+		},
+	})
+}
+
+// ParseLines behaves as Parse, but reads its input from lines, each of which
+// is one line of input without its line terminator. Locations are reported
+// relative to start, which gives the line number of the first line, the
+// section in effect before it, and the file it came from. If start.Line is 0,
+// the lines are numbered from 1. This allows a preprocessor to deliver its
+// output to the parser while preserving the locations of the original input.
+func ParseLines(lines []string, start Location, h Handler) error {
+	if start.Line > 0 {
+		start.Line-- // the location before the first line
+	}
+	return parse(strings.NewReader(strings.Join(lines, "\n")), h, parseConfig{start: start})
+}
+
+// defaultProgressLines is the default interval for Handler.Progress.
+const defaultProgressLines = 1000
+
+// ordinals tracks the positions of sections and keys for Handler.Ordinals.
+type ordinals struct {
+	section, key int
+}
+
+// defaultBufferSize is the default for Handler.BufferSize.
+const defaultBufferSize = 4096
+
+// defaultMaxReuseCap is the default limit for Handler.MaxReuseCap.
+const defaultMaxReuseCap = 1024
+
+// valuesPool holds values slices for reuse when Handler.ReuseValues is set.
+var valuesPool = sync.Pool{New: func() interface{} { return new([]string) }}
+
+// Values of resync, describing what input to skip after a recovered error.
+const (
+	resyncKey     = 1 // skip to the next key or section header
+	resyncSection = 2 // skip to the next section header
+)
+
+// parseConfig carries internal settings for parse.
+type parseConfig struct {
+	keep  func(string) bool // if non-nil, which sections to deliver
+	start Location          // the location before the first line of input
+	one   bool              // if true, stop before a second section header
+	depth int               // the number of enclosing included files
+	last  *string           // if non-nil, receives the final section name
+
+	// If non-nil, names holds interned key and section names.
+	names map[string]string
+
+	// If non-nil, ord holds the current ordinals for Handler.Ordinals.
+	ord *ordinals
+
+	// If set, cont is called for each continuation line with the location of
+	// the line, the location and name of the key it continues, the raw text
+	// of the line, and the byte offset of the line in the input.
+	cont func(loc, keyLoc Location, key, text string, offset int64)
+
+	// If set, line is called with the classification of each line of input.
+	// If it reports an error, parsing stops and that error is returned.
+	line func(LineInfo) error
+}
+
+// parse implements Parse and its variations.
+func parse(r io.Reader, h Handler, cfg parseConfig) error {
+	if h.ContinueOnError {
+		var errs ErrorList
+		err := parse(r, h.continueOnError(&errs), cfg)
+		if len(errs.Errors) == 0 {
+			return err
+		} else if err != nil {
+			errs.Add(err)
+		}
+		return &errs
+	}
+
+	headerRE, err := h.Dialect.headerPattern()
+	if err != nil {
+		return err
+	}
+	buf, pos := newLineScanner(r, h.BufferSize, h.MaxLineSize)
+	loc := cfg.start // current physical input location
+	loc.Tag = h.Tag
+	keep := cfg.keep
+	skip := keep != nil && !keep(loc.Section)
+	headers := 0 // number of section headers seen
+	var nest sectionNest
+
+	var keyLoc Location    // location of curKey
+	var curKey string      // current key being processed
+	var values []string    // values for curKey
+	var indents []string   // indentation of the lines of values
+	var vcs []ValueComment // comments in the block of the current value
+	nextIndex := -1        // next index in a run of numbered keys, or -1
+	var runBase string     // the base name of the run of numbered keys
+	joined := 0            // number of lines joined to the previous line
+	blanks := 0            // number of blank lines since the last non-blank
+	resync := 0            // what input to skip after a recovered error
+	var text string        // the text of the current line
+
+	if h.Ordinals && cfg.ord == nil {
+		cfg.ord = new(ordinals)
+	}
+	if h.InternNames && cfg.names == nil {
+		cfg.names = make(map[string]string)
+	}
+	// intern returns the interned copy of name, if InternNames is set.
+	intern := func(name string) string {
+		if cfg.names == nil {
+			return name
+		} else if s, ok := cfg.names[name]; ok {
+			return s
+		}
+		cfg.names[name] = name
+		return name
+	}
+
+	if h.ReuseValues {
+		p := valuesPool.Get().(*[]string)
+		values = (*p)[:0]
+		defer func() {
+			limit := h.MaxReuseCap
+			if limit <= 0 {
+				limit = defaultMaxReuseCap
+			}
+			if cap(values) <= limit {
+				*p = values[:0]
+				valuesPool.Put(p)
+			}
+		}()
+	}
+
+	type heldComment struct {
+		loc  Location
+		text string
+	}
+	var held []heldComment // comments held for AttachComments
+
+	type openBlock struct {
+		loc  Location // the location of the start of the block
+		name string   // the full path name of the block
+	}
+	var blocks []openBlock // brace blocks not yet closed
+
+	// attach returns a copy of loc with any held comments attached.
+	attach := func(loc Location) Location {
+		for _, c := range held {
+			loc.Comments = append(loc.Comments, c.text)
+		}
+		held = nil
+		return loc
+	}
+
+	// headerLoc returns the location to report for a section header.
+	headerLoc := func() Location {
+		hloc := attach(loc)
+		if h.RawHeaders {
+			hloc.Raw = text
+		}
+		if cfg.ord != nil {
+			hloc.SectionOrdinal = cfg.ord.section
+		}
+		return hloc
+	}
+
+	// nextSection advances the ordinals past a section header.
+	nextSection := func() {
+		if cfg.ord != nil {
+			cfg.ord.section++
+			cfg.ord.key = 0
+		}
+	}
 
-	var keyLoc Location // location of curKey
-	var curKey string   // current key being processed
-	var values []string // values for curKey
+	// keyOrdinals returns a copy of kloc with the ordinals of a key.
+	keyOrdinals := func(kloc Location) Location {
+		if cfg.ord != nil {
+			cfg.ord.key++
+			kloc.SectionOrdinal = cfg.ord.section
+			kloc.KeyOrdinal = cfg.ord.key
+		}
+		return kloc
+	}
+
+	// addIndent records the indentation of a value, if ValueIndents is set.
+	addIndent := func(indent string) {
+		if h.ValueIndents {
+			indents = append(indents, indent)
+		}
+	}
 
 	emit := func() error {
-		defer func() { curKey = ""; values = nil }()
+		defer func() {
+			curKey, indents, vcs, nextIndex, runBase = "", nil, nil, -1, ""
+			if h.ReuseValues {
+				for i := range values {
+					values[i] = "" // release the strings
+				}
+				values = values[:0]
+			} else {
+				values = nil
+			}
+		}()
 		if curKey == "" {
 			return nil
+		} else if h.Dialect.ListValues {
+			values, indents = splitList(values, indents)
+		}
+		for _, norm := range h.Dialect.Normalizers {
+			for i, v := range values {
+				values[i] = norm(v)
+			}
+		}
+		if h.ValueIndents {
+			keyLoc.Indents = indents
+		}
+		keyLoc.ValueComments = vcs
+		return h.keyValue(keyOrdinals(keyLoc), curKey, values)
+	}
+
+	// note reports the classification of the current line to cfg.line.
+	note := func(kind string) error {
+		if cfg.line == nil {
+			return nil
+		}
+		return cfg.line(LineInfo{Line: loc.Line, File: loc.File, Kind: kind, Text: text})
+	}
+
+	// salvage reports err to h.Recover, if it is a syntax error that can be
+	// recovered. If h.Recover accepts it, salvage delivers any pending key and
+	// returns nil; the caller should then set resync and skip the line.
+	salvage := func(err error) error {
+		se, ok := err.(*SyntaxError)
+		if !ok || h.Recover == nil {
+			return err
+		} else if err := h.Recover(se); err != nil {
+			return err
+		}
+		return emit()
+	}
+
+	// closeBlocks reports an error for the innermost open brace block, if
+	// any. If the error is recovered, all the open blocks are discarded.
+	closeBlocks := func() error {
+		if len(blocks) == 0 {
+			return nil
+		}
+		b := blocks[len(blocks)-1]
+		if err := salvage(syntaxError(b.loc, msgUnclosedBlock, b.name)); err != nil {
+			return err
+		}
+		blocks = nil
+		return nil
+	}
+
+	every := h.ProgressLines
+	if every <= 0 {
+		every = defaultProgressLines
+	}
+	nextReport := cfg.start.Line + every
+	progress := func() error {
+		if h.Progress == nil {
+			return nil
 		}
-		return h.keyValue(keyLoc, curKey, values)
+		_, end := pos()
+		return h.Progress(end, loc.Line+joined-cfg.start.Line)
 	}
 
 	for buf.Scan() {
-		loc.Line++
-		text := buf.Text()
-		clean := strings.TrimSpace(text)
+		loc.Line += 1 + joined
+		text = buf.Text()
+		if joined = 0; h.Dialect.LineContinuation {
+			text, joined = joinLines(buf, text)
+		}
+		if loc.Line+joined >= nextReport {
+			if err := progress(); err != nil {
+				return err
+			}
+			nextReport += every
+		}
+		shape := h.Dialect.scanLine(text)
+		clean := shape.clean
 		if clean == "" {
+			if err := note(LineBlank); err != nil {
+				return err
+			}
+			blanks++
 			continue // skip blank lines
 		}
-		isIndented := text != "" && (text[0] == ' ' || text[0] == '\t')
+		gap := blanks
+		blanks = 0
+		blockName, isBlockStart := h.Dialect.blockStart(clean)
+		isBlockEnd := h.Dialect.isBlockEnd(clean)
+		isIndented := shape.indent != 0 && (h.Dialect.UniformSpace || text[0] == ' ' || text[0] == '\t')
+		if resync != 0 {
+			isKey := !isIndented && !h.Dialect.isComment(clean)
+			if clean[0] != '[' && !isBlockStart && (resync == resyncSection || !isKey) {
+				continue // skip damaged input
+			}
+			resync = 0
+		}
+		if h.Recover != nil && !utf8.ValidString(text) {
+			if err := salvage(syntaxError(loc, msgInvalidUTF8, "")); err != nil {
+				return err
+			} else if resync = resyncKey; clean[0] == '[' {
+				resync = resyncSection
+			}
+			continue
+		}
+		if skip && clean[0] != '[' && !isBlockStart && !isBlockEnd {
+			continue // skip the contents of unwanted sections
+		}
 
-		if strings.HasPrefix(clean, ";") {
+		if h.Dialect.MultilineValues && isIndented && curKey != "" && nextIndex < 0 {
+			if h.Dialect.isComment(clean) {
+				blanks = gap // comments do not separate lines of the block
+				if err := note(LineComment); err != nil {
+					return err
+				}
+				switch {
+				case h.BlockComments == BlockCommentsAttach:
+					vcs = append(vcs, ValueComment{
+						Offset: strings.Count(values[len(values)-1], "\n") + 1 + gap,
+						Text:   text,
+					})
+				case h.BlockComments == BlockCommentsIgnore:
+				case h.AttachComments:
+					held = append(held, heldComment{loc, text})
+				default:
+					if err := h.comment(loc, text); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if err := note(LineContinuation); err != nil {
+				return err
+			}
+			values[len(values)-1] += strings.Repeat("\n", gap+1) + clean
+			continue
+		}
+
+		if h.Dialect.isComment(clean) {
+			if err := note(LineComment); err != nil {
+				return err
+			}
+			if isIndented && curKey != "" && h.BlockComments != BlockCommentsDeliver {
+				// The comment is among the indented values of the key.
+				if h.BlockComments == BlockCommentsAttach {
+					n := len(values)
+					if n == 1 && values[0] == "" {
+						n = 0 // no value yet, only the empty key line
+					}
+					vcs = append(vcs, ValueComment{Offset: n, Text: text})
+				}
+				continue
+			}
 			if err := emit(); err != nil {
 				return err
+			} else if h.AttachComments {
+				held = append(held, heldComment{loc, text})
 			} else if err := h.comment(loc, text); err != nil {
 				return err
 			}
 			continue
 		}
 
+		if isBlockStart || isBlockEnd {
+			if err := note(LineSection); err != nil {
+				return err
+			} else if err := emit(); err != nil {
+				return err
+			}
+			var name string
+			if isBlockStart {
+				name = blockName
+				if len(blocks) != 0 {
+					name = blocks[len(blocks)-1].name + "/" + name
+				}
+				name = intern(name)
+				blocks = append(blocks, openBlock{loc, name})
+			} else if len(blocks) == 0 {
+				if err := salvage(syntaxError(loc, msgUnmatchedBlock, "")); err != nil {
+					return err
+				}
+				resync = resyncKey
+				continue
+			} else if blocks = blocks[:len(blocks)-1]; len(blocks) != 0 {
+				name = blocks[len(blocks)-1].name
+			}
+			nextSection()
+			skip = keep != nil && !keep(name)
+			if skip {
+				held = nil
+			} else if err := h.section(headerLoc(), name); err != nil {
+				return err
+			}
+			loc.Section = name
+			continue
+		}
+
 		if clean[0] == '[' {
-			if clean[len(clean)-1] != ']' {
-				return syntaxError(loc, msgUnclosedHeader, clean[1:])
+			if err := closeBlocks(); err != nil {
+				return err
 			}
-			name := cleanKey(clean[1 : len(clean)-1])
-			if name == "" || strings.ContainsAny(name, "[]") {
-				return syntaxError(loc, msgInvalidSection, name)
-			} else if err := emit(); err != nil {
+			headers++
+			if cfg.one && headers > 1 {
+				break
+			}
+			if err := note(LineSection); err != nil {
 				return err
-			} else if err := h.section(loc, name); err != nil {
+			}
+			name, err := h.Dialect.parseHeader(loc, clean)
+			if err != nil {
+				if err := salvage(err); err != nil {
+					return err
+				}
+				resync = resyncSection
+				continue
+			} else if err := emit(); err != nil {
 				return err
 			}
+			caps := headerCaptures(headerRE, name)
+			if h.Dialect.NestedSections {
+				if name == "" {
+					nest = nil
+				} else {
+					name = nest.push(utf8.RuneCountInString(h.Dialect.indent(text)), name)
+				}
+			}
+			name = intern(name)
+			nextSection()
+			skip = keep != nil && !keep(name)
+			if skip {
+				held = nil // discard comments on the skipped section
+			} else {
+				hloc := headerLoc()
+				hloc.Captures = caps
+				if err := h.section(hloc, name); err != nil {
+					return err
+				}
+			}
 			loc.Section = name
 			continue
 		}
 
-		i := strings.Index(clean, "=")
+		i := shape.eq
 		if i < 0 {
 			// If a bare key is indented, it may be the value for a previous key.
 			if isIndented && curKey != "" {
+				if err := note(LineContinuation); err != nil {
+					return err
+				}
+				if cfg.cont != nil {
+					start, _ := pos()
+					cfg.cont(loc, keyLoc, curKey, text, start)
+				}
 				if len(values) == 1 && values[0] == "" {
 					values[0] = clean
+					if h.ValueIndents {
+						indents[0] = h.Dialect.indent(text)
+					}
 				} else {
 					values = append(values, clean)
+					addIndent(h.Dialect.indent(text))
 				}
 				continue
 			}
@@ -216,33 +976,134 @@ func Parse(r io.Reader, h Handler) error {
 			// more values, this is a new key with no value. Because there is no
 			// equal sign to support continuations, this key cannot have more than
 			// one value of its own so we bypass accumulation
-			if err := emit(); err != nil {
+			key := h.Dialect.cleanKey(clean)
+			if err := note(LineKey); err != nil {
 				return err
-			} else if err := h.keyValue(loc, cleanKey(clean), []string{""}); err != nil {
+			} else if err := h.Dialect.checkKey(loc, key); err != nil {
+				if err := salvage(err); err != nil {
+					return err
+				}
+				resync = resyncKey
+				continue
+			} else if err := emit(); err != nil {
+				return err
+			}
+			kloc := keyOrdinals(attach(loc))
+			if h.ValueIndents {
+				kloc.Indents = []string{""}
+			}
+			if err := h.keyValue(kloc, intern(h.Dialect.keyName(key)), []string{""}); err != nil {
 				return err
 			}
 			continue
 		}
 
 		// At this point we have a key=value pair, which we must accumulate.
-		key := cleanKey(clean[:i])
+		key := h.Dialect.cleanKey(clean[:i])
+		err := h.Dialect.checkKey(loc, key)
 		if key == "" {
-			return syntaxError(loc, msgEmptyKey, "")
+			err = syntaxError(loc, msgEmptyKey, "")
 		}
-		value := strings.TrimSpace(clean[i+1:])
-		if key != curKey {
+		if err != nil {
+			if err := salvage(err); err != nil {
+				return err
+			}
+			resync = resyncKey
+			continue
+		} else if err := note(LineKey); err != nil {
+			return err
+		}
+		key = intern(h.Dialect.keyName(key))
+		value := h.Dialect.trimSpace(clean[i+1:])
+		if h.Dialect.PHPValues {
+			value = h.Dialect.phpValue(value)
+		}
+		if h.Dialect.QuotedValues {
+			value = unquoteValue(value)
+		}
+		if h.Dialect.PercentEscapes {
+			value = percentReplacer.Replace(value)
+		}
+		if h.Dialect.IncludeKey != "" && key == h.Dialect.IncludeKey {
+			if err := emit(); err != nil {
+				return err
+			}
+			name, err := include(loc, value, h, cfg)
+			if err != nil {
+				if err := salvage(err); err != nil {
+					return err
+				}
+				continue
+			}
+			loc.Section = name
+			skip = keep != nil && !keep(name)
+			continue
+		}
+		if h.NumberedKeys {
+			if base, n, ok := splitNumberedKey(key); ok {
+				if base == runBase && n == nextIndex {
+					curKey = base // the run is confirmed by its second key
+					nextIndex++
+					values = append(values, value)
+					addIndent("")
+					continue
+				} else if n <= 1 {
+					if err := emit(); err != nil {
+						return err
+					}
+					keyLoc = attach(loc)
+					curKey, runBase = key, base
+					nextIndex = n + 1
+					values = append(values, value)
+					addIndent("")
+					continue
+				}
+			}
+		}
+		if key != curKey || nextIndex >= 0 {
 			if err := emit(); err != nil {
 				return err
 			}
-			keyLoc = loc
+			keyLoc = attach(loc)
 			curKey = key
 		}
 		values = append(values, value)
+		addIndent("")
 	}
 	if err := buf.Err(); err != nil {
+		return &IOError{Location: Location{Line: loc.Line + 1, File: loc.File, Tag: loc.Tag}, Err: err}
+	} else if err := emit(); err != nil { // emit any leftover key/values
+		return err
+	} else if err := closeBlocks(); err != nil {
+		return err
+	}
+	if cfg.last != nil {
+		*cfg.last = loc.Section
+	}
+	if err := progress(); err != nil {
 		return err
 	}
-	return emit() // emit any leftover key/values
+	for _, c := range held {
+		if err := h.comment(c.loc, c.text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitNumberedKey reports whether key has the form "base.N" or "baseN" for
+// a non-empty base and a decimal index N, and if so returns base and N.
+func splitNumberedKey(key string) (base string, n int, ok bool) {
+	i := len(key)
+	for i > 0 && key[i-1] >= '0' && key[i-1] <= '9' {
+		i--
+	}
+	if i == len(key) || len(key)-i > 9 {
+		return "", 0, false // no index, or too long to be reasonable
+	}
+	n, _ = strconv.Atoi(key[i:])
+	base = cleanKey(strings.TrimSuffix(key[:i], "."))
+	return base, n, base != ""
 }
 
 func cleanKey(key string) string {
@@ -15,10 +15,19 @@
 package ini_test
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"unsafe"
 
 	"github.com/creachadair/ini"
 	"github.com/google/go-cmp/cmp"
@@ -119,25 +128,31 @@ var tests = []struct {
 	}},
 }
 
-func runParser(s string) ([]result, error) {
+func runParser(s string) ([]result, error) { return runParserWith(ini.Handler{}, s) }
+
+// runParserWith parses s using the options set in h, replacing its callbacks.
+func runParserWith(h ini.Handler, s string) ([]result, error) {
 	var got []result
+	err := ini.Parse(strings.NewReader(s), recordTo(&got, h))
+	return got, err
+}
+
+// recordTo returns a copy of h whose callbacks append results to *got.
+func recordTo(got *[]result, h ini.Handler) ini.Handler {
 	push := func(r result) error {
-		got = append(got, r)
+		*got = append(*got, r)
 		return nil
 	}
-
-	err := ini.Parse(strings.NewReader(s), ini.Handler{
-		Comment: func(loc ini.Location, text string) error {
-			return push(result{loc.Line, "comment", "", nil})
-		},
-		Section: func(loc ini.Location, name string) error {
-			return push(result{loc.Line, "section", name, nil})
-		},
-		KeyValue: func(loc ini.Location, key string, values []string) error {
-			return push(result{loc.Line, "key/value", key, values})
-		},
-	})
-	return got, err
+	h.Comment = func(loc ini.Location, text string) error {
+		return push(result{loc.Line, "comment", "", nil})
+	}
+	h.Section = func(loc ini.Location, name string) error {
+		return push(result{loc.Line, "section", name, nil})
+	}
+	h.KeyValue = func(loc ini.Location, key string, values []string) error {
+		return push(result{loc.Line, "key/value", key, values})
+	}
+	return h
 }
 
 func TestParse(t *testing.T) {
@@ -156,11 +171,1048 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestNumberedKeys(t *testing.T) {
+	const input = `
+path.0 = /bin
+path.1 = /usr/bin
+path.2 = /usr/local/bin
+  /opt/bin
+x1 = a
+x2 = b
+x4 = c
+port8080 = yes
+item.1 = p
+item.2 = q
+item.1 = r
+plain = s
+plain = t
+sha1 = u
+level0 = v
+level2 = w
+`
+	want := []result{
+		{2, "key/value", "path", []string{"/bin", "/usr/bin", "/usr/local/bin", "/opt/bin"}},
+		{6, "key/value", "x", []string{"a", "b"}},
+		{8, "key/value", "x4", []string{"c"}},         // skipped index, not in the run
+		{9, "key/value", "port8080", []string{"yes"}}, // does not start at 0 or 1
+		{10, "key/value", "item", []string{"p", "q"}},
+		{12, "key/value", "item.1", []string{"r"}}, // a lone key is not a run
+		{13, "key/value", "plain", []string{"s", "t"}},
+		{15, "key/value", "sha1", []string{"u"}},
+		{16, "key/value", "level0", []string{"v"}},
+		{17, "key/value", "level2", []string{"w"}},
+	}
+	got, err := runParserWith(ini.Handler{NumberedKeys: true}, input)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+}
+
+func TestEscapedBrackets(t *testing.T) {
+	h := ini.Handler{Dialect: ini.Dialect{EscapedBrackets: true}}
+	got, err := runParserWith(h, `[module\[1\]]
+[ a \] b ]
+[back\\slash]
+[other\x]
+`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "section", "module[1]", nil},
+		{2, "section", "a ] b", nil},
+		{3, "section", `back\slash`, nil},
+		{4, "section", `other\x`, nil},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+
+	for _, input := range []string{`[bad\]`, `[bad]]`, `[[bad]`, `[\\]]`} {
+		if _, err := runParserWith(h, input); err == nil {
+			t.Errorf("Parse(%q): got nil, want error", input)
+		}
+	}
+}
+
+func TestWindowsPaths(t *testing.T) {
+	const input = `[paths]
+app = C:\Program Files\App
+share = \\server\share\
+list = D:\a
+  E:\[b]\
+`
+	want := []result{
+		{1, "section", "paths", nil},
+		{2, "key/value", "app", []string{`C:\Program Files\App`}},
+		{3, "key/value", "share", []string{`\\server\share\`}},
+		{4, "key/value", "list", []string{`D:\a`, `E:\[b]\`}},
+	}
+	for _, d := range []ini.Dialect{{}, {EscapedBrackets: true}} {
+		got, err := runParserWith(ini.Handler{Dialect: d}, input)
+		if err != nil {
+			t.Fatalf("Parse %+v failed: %v", d, err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Parse %+v results (-want, +got)\n%s", d, diff)
+		}
+	}
+}
+
+func TestSamba(t *testing.T) {
+	h := ini.Handler{Dialect: ini.Samba}
+	got, err := runParserWith(h, `# global settings
+[global]
+  Workgroup = HOME
+  hosts allow = 10.0.0.1 \
+     10.0.0.2
+; share definitions
+[My  Files]
+  Path = /srv/files
+  Writable = yes
+BROWSABLE
+`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "comment", "", nil},
+		{2, "section", "global", nil},
+		{3, "key/value", "workgroup", []string{"HOME"}},
+		{4, "key/value", "hosts allow", []string{"10.0.0.1      10.0.0.2"}},
+		{6, "comment", "", nil},
+		{7, "section", "My Files", nil},
+		{8, "key/value", "path", []string{"/srv/files"}},
+		{9, "key/value", "writeable", []string{"yes"}},
+		{10, "key/value", "browseable", []string{""}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+
+	// Without the dialect, "#" does not begin a comment.
+	got, err = runParser("# note\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if diff := cmp.Diff([]result{{1, "key/value", "# note", []string{""}}}, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+}
+
+func TestUnicodeSpace(t *testing.T) {
+	const input = "[\u3000s\u3000]\nk\u00a0x = a\u00a0\n\u00a0b\n"
+	tests := []struct {
+		dialect ini.Dialect
+		want    []result
+	}{
+		{ini.Dialect{}, []result{
+			{1, "section", "s", nil},
+			{2, "key/value", "k x", []string{"a"}},
+			{3, "key/value", "b", []string{""}},
+		}},
+		{ini.Dialect{UniformSpace: true}, []result{
+			{1, "section", "s", nil},
+			{2, "key/value", "k x", []string{"a", "b"}},
+		}},
+		{ini.Dialect{ASCIISpace: true, UniformSpace: true}, []result{
+			{1, "section", "\u3000s\u3000", nil},
+			{2, "key/value", "k\u00a0x", []string{"a\u00a0"}},
+			{3, "key/value", "\u00a0b", []string{""}},
+		}},
+		{ini.Dialect{ASCIISpace: true}, []result{
+			{1, "section", "\u3000s\u3000", nil},
+			{2, "key/value", "k\u00a0x", []string{"a\u00a0"}},
+			{3, "key/value", "\u00a0b", []string{""}},
+		}},
+	}
+	for _, test := range tests {
+		got, err := runParserWith(ini.Handler{Dialect: test.dialect}, input)
+		if err != nil {
+			t.Fatalf("Parse %+v failed: %v", test.dialect, err)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("Parse %+v results (-want, +got)\n%s", test.dialect, diff)
+		}
+	}
+}
+
+func TestStrictKeys(t *testing.T) {
+	h := ini.Handler{Dialect: ini.Dialect{StrictKeys: true}}
+	got, err := runParserWith(h, "a.b-c_d = 1\nkey with spaces\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "key/value", "a.b-c_d", []string{"1"}},
+		{2, "key/value", "key with spaces", []string{""}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+
+	tests := []struct {
+		input, key string
+	}{
+		{"ok = 1\nbad\x01key = 2\n", `"bad\x01key"`},
+		{"ok = 1\n\x7f\n", `"\x7f"`},
+	}
+	for _, test := range tests {
+		_, err := runParserWith(h, test.input)
+		if e, ok := err.(*ini.SyntaxError); !ok || e.Desc != msgInvalidKey || e.Key != test.key || e.Line != 2 {
+			t.Errorf("Parse(%q): got %v, want line 2: %s: %s", test.input, err, msgInvalidKey, test.key)
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	const input = `a = 1
+  more
+[broken
+lost = 2
+[ok]
+= 3
+  lost
+b = 4
+bad` + "\xff" + ` = 5
+  lost
+c = 6
+; comment
+[` + "\xfe" + `]
+lost = 7
+[end]
+d = 8
+`
+	var errs ini.ErrorList
+	h := ini.Handler{Recover: func(e *ini.SyntaxError) error { errs.Add(e); return nil }}
+	got, err := runParserWith(h, input)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "key/value", "a", []string{"1", "more"}},
+		{5, "section", "ok", nil},
+		{8, "key/value", "b", []string{"4"}},
+		{11, "key/value", "c", []string{"6"}},
+		{12, "comment", "", nil},
+		{15, "section", "end", nil},
+		{16, "key/value", "d", []string{"8"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse (-want, +got)\n%s", diff)
+	}
+
+	wantErrs := []struct {
+		line int
+		desc string
+	}{
+		{3, msgUnclosedHeader},
+		{6, msgEmptyKey},
+		{9, msgInvalidUTF8},
+		{13, msgInvalidUTF8},
+	}
+	if len(errs.Errors) != len(wantErrs) {
+		t.Fatalf("Got %d errors, want %d: %v", len(errs.Errors), len(wantErrs), errs.Errors)
+	}
+	for i, w := range wantErrs {
+		if e := errs.Errors[i].(*ini.SyntaxError); e.Line != w.line || e.Desc != w.desc {
+			t.Errorf("Error %d: got %v, want line %d: %s", i, e, w.line, w.desc)
+		}
+	}
+
+	// An error from Recover stops the parse.
+	errStop := errors.New("stop")
+	h.Recover = func(*ini.SyntaxError) error { return errStop }
+	if _, err := runParserWith(h, input); err != errStop {
+		t.Errorf("Parse: got %v, want %v", err, errStop)
+	}
+}
+
+func TestRecoverBlocksAndIncludes(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.ini")
+	if err := os.WriteFile(good, []byte("x = 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	input := "include = " + filepath.Join(dir, "missing.ini") + `
+include = [
+include = ` + good + `
+a = {
+b = 1
+[s]
+c = {
+d = 2
+`
+	var errs ini.ErrorList
+	h := ini.Handler{
+		Dialect: ini.Dialect{IncludeKey: "include", BraceBlocks: true},
+		Recover: func(e *ini.SyntaxError) error { errs.Add(e); return nil },
+	}
+	got, err := runParserWith(h, input)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "key/value", "x", []string{"1"}},
+		{4, "section", "a", nil},
+		{5, "key/value", "b", []string{"1"}},
+		{6, "section", "s", nil},
+		{7, "section", "c", nil},
+		{8, "key/value", "d", []string{"2"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse (-want, +got)\n%s", diff)
+	}
+
+	wantErrs := []struct {
+		line int
+		desc string
+	}{
+		{1, msgIncludeOpen},
+		{2, msgInvalidInclude},
+		{4, msgUnclosedBlock},
+		{7, msgUnclosedBlock},
+	}
+	if len(errs.Errors) != len(wantErrs) {
+		t.Fatalf("Got %d errors, want %d: %v", len(errs.Errors), len(wantErrs), errs.Errors)
+	}
+	for i, w := range wantErrs {
+		if e := errs.Errors[i].(*ini.SyntaxError); e.Line != w.line || e.Desc != w.desc {
+			t.Errorf("Error %d: got %v, want line %d: %s", i, e, w.line, w.desc)
+		}
+	}
+
+	// Without Recover, the first failure stops the parse.
+	h.Recover = nil
+	if _, err := runParserWith(h, input); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Parse: got %v, want a not-exist error", err)
+	}
+}
+
+func TestRawHeaders(t *testing.T) {
+	const input = "[quoted_fields]   \n  [ a   b ]\nk = v\n"
+	var got []ini.Location
+	h := ini.Handler{
+		RawHeaders: true,
+		Section: func(loc ini.Location, name string) error {
+			got = append(got, loc)
+			return nil
+		},
+	}
+	if err := ini.Parse(strings.NewReader(input), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []ini.Location{
+		{Line: 1, Raw: "[quoted_fields]   "},
+		{Line: 2, Section: "quoted_fields", Raw: "  [ a   b ]"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Section locations (-want, +got)\n%s", diff)
+	}
+}
+
+func TestValueIndents(t *testing.T) {
+	const input = "a = 1\n  2\n\t\t3\nb =\n    x\nc\nd.1 = p\nd.2 = q\n"
+	var got []ini.Entry
+	h := ini.CollectOrdered(&got)
+	h.ValueIndents = true
+	h.NumberedKeys = true
+	if err := ini.Parse(strings.NewReader(input), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := [][]string{{"", "  ", "\t\t"}, {"    "}, {""}, {"", ""}}
+	if len(got) != len(want) {
+		t.Fatalf("Got %d keys, want %d", len(got), len(want))
+	}
+	for i, e := range got {
+		if diff := cmp.Diff(want[i], e.Indents); diff != "" {
+			t.Errorf("Key %q indents (-want, +got)\n%s", e.Key, diff)
+		}
+	}
+
+	// Values split from a line have the indentation of that line.
+	got = nil
+	h.Dialect = ini.Dialect{ListValues: true}
+	if err := ini.Parse(strings.NewReader("x = a, b\n  c,d\n"), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"", "", "  ", "  "}, got[0].Indents); diff != "" {
+		t.Errorf("List indents (-want, +got)\n%s", diff)
+	}
+}
+
+func TestBlockComments(t *testing.T) {
+	const input = "a =\n  x\n  ; one\n\n  y\n  ; two\nb = 1\n"
+	tests := []struct {
+		mode     ini.BlockCommentMode
+		comments []string
+		vcs      []ini.ValueComment
+	}{
+		{ini.BlockCommentsDeliver, []string{"  ; one", "  ; two"}, nil},
+		{ini.BlockCommentsAttach, nil, []ini.ValueComment{{Offset: 2, Text: "  ; one"}, {Offset: 4, Text: "  ; two"}}},
+		{ini.BlockCommentsIgnore, nil, nil},
+	}
+	for _, test := range tests {
+		var comments []string
+		var keys []ini.Location
+		h := ini.Handler{
+			Dialect:       ini.Dialect{MultilineValues: true},
+			BlockComments: test.mode,
+			Comment: func(_ ini.Location, text string) error {
+				comments = append(comments, text)
+				return nil
+			},
+			KeyValue: func(loc ini.Location, key string, values []string) error {
+				if key == "a" && values[0] != "\nx\n\ny" {
+					t.Errorf("Mode %d: value of a is %q", test.mode, values[0])
+				}
+				keys = append(keys, loc)
+				return nil
+			},
+		}
+		if err := ini.Parse(strings.NewReader(input), h); err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if diff := cmp.Diff(test.comments, comments); diff != "" {
+			t.Errorf("Mode %d comments (-want, +got)\n%s", test.mode, diff)
+		}
+		if len(keys) != 2 {
+			t.Fatalf("Mode %d: got %d keys, want 2", test.mode, len(keys))
+		}
+		if diff := cmp.Diff(test.vcs, keys[0].ValueComments); diff != "" {
+			t.Errorf("Mode %d value comments (-want, +got)\n%s", test.mode, diff)
+		}
+		if keys[1].ValueComments != nil {
+			t.Errorf("Mode %d: b has value comments %+v", test.mode, keys[1].ValueComments)
+		}
+	}
+}
+
+func TestBlockCommentsDefault(t *testing.T) {
+	// Without MultilineValues, the indented lines after a key are further
+	// values of it, and a comment among them need not end the key.
+	const input = "k = a\n  ; c\n  b\nj = 1\n"
+	type kv struct {
+		Key    string
+		Values []string
+		VCs    []ini.ValueComment
+	}
+	tests := []struct {
+		mode     ini.BlockCommentMode
+		comments []string
+		keys     []kv
+	}{
+		{ini.BlockCommentsDeliver, []string{"  ; c"}, []kv{
+			{"k", []string{"a"}, nil}, {"b", []string{""}, nil}, {"j", []string{"1"}, nil},
+		}},
+		{ini.BlockCommentsAttach, nil, []kv{
+			{"k", []string{"a", "b"}, []ini.ValueComment{{Offset: 1, Text: "  ; c"}}}, {"j", []string{"1"}, nil},
+		}},
+		{ini.BlockCommentsIgnore, nil, []kv{
+			{"k", []string{"a", "b"}, nil}, {"j", []string{"1"}, nil},
+		}},
+	}
+	for _, test := range tests {
+		var comments []string
+		var keys []kv
+		h := ini.Handler{
+			BlockComments: test.mode,
+			Comment: func(_ ini.Location, text string) error {
+				comments = append(comments, text)
+				return nil
+			},
+			KeyValue: func(loc ini.Location, key string, values []string) error {
+				keys = append(keys, kv{key, values, loc.ValueComments})
+				return nil
+			},
+		}
+		if err := ini.Parse(strings.NewReader(input), h); err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if diff := cmp.Diff(test.comments, comments); diff != "" {
+			t.Errorf("Mode %d comments (-want, +got)\n%s", test.mode, diff)
+		}
+		if diff := cmp.Diff(test.keys, keys); diff != "" {
+			t.Errorf("Mode %d keys (-want, +got)\n%s", test.mode, diff)
+		}
+	}
+
+	// A comment before the first indented value has no value before it.
+	var vcs []ini.ValueComment
+	err := ini.Parse(strings.NewReader("k =\n  ; c\n  a\n"), ini.Handler{
+		BlockComments: ini.BlockCommentsAttach,
+		KeyValue: func(loc ini.Location, _ string, _ []string) error {
+			vcs = loc.ValueComments
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if diff := cmp.Diff([]ini.ValueComment{{Offset: 0, Text: "  ; c"}}, vcs); diff != "" {
+		t.Errorf("Leading comment (-want, +got)\n%s", diff)
+	}
+}
+
+func TestHeaderPattern(t *testing.T) {
+	const input = "[host \"db1\" port=5432]\n[plain]\n[host \"db2\" port=x]\n"
+	var got []ini.Location
+	var names []string
+	h := ini.Handler{
+		Dialect: ini.Dialect{HeaderPattern: `^host "(?P<host>[^"]*)" port=(?P<port>\d+)$`},
+		Section: func(loc ini.Location, name string) error {
+			got = append(got, loc)
+			names = append(names, name)
+			return nil
+		},
+	}
+	if err := ini.Parse(strings.NewReader(input), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []ini.Location{
+		{Line: 1, Captures: map[string]string{"host": "db1", "port": "5432"}},
+		{Line: 2, Section: `host "db1" port=5432`},
+		{Line: 3, Section: "plain"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Section locations (-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{`host "db1" port=5432`, "plain", `host "db2" port=x`}, names); diff != "" {
+		t.Errorf("Section names (-want, +got)\n%s", diff)
+	}
+
+	h.Dialect.HeaderPattern = "("
+	if err := ini.Parse(strings.NewReader(input), h); err == nil {
+		t.Error("Parse with a bad pattern: got nil error")
+	}
+}
+
+func TestTag(t *testing.T) {
+	var events []ini.Event
+	h := ini.EventHandler(func(e ini.Event) error {
+		events = append(events, e)
+		return nil
+	})
+	h.Tag = "src1"
+	if err := ini.Parse(strings.NewReader("; c\na = 1\n[s]\nb = 2\n"), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("Got %d events, want 4", len(events))
+	}
+	for _, e := range events {
+		if e.Tag != "src1" || e.Location().Tag != "src1" {
+			t.Errorf("Event %+v: got tag %q, want src1", e, e.Tag)
+		}
+	}
+
+	err := ini.Parse(strings.NewReader("[bad\n"), h)
+	if loc := ini.ErrorLocation(err); loc.Tag != "src1" {
+		t.Errorf("Parse: got error %v with tag %q, want src1", err, loc.Tag)
+	}
+	err = ini.ParseGitEnv([]string{"GIT_CONFIG_COUNT=1"}, h)
+	if loc := ini.ErrorLocation(err); loc.Tag != "src1" {
+		t.Errorf("ParseGitEnv: got error %v with tag %q, want src1", err, loc.Tag)
+	}
+}
+
+func TestReuseValues(t *testing.T) {
+	const input = "a = 1\n  2\n  3\nb = 4\n[s]\nc = 5\n  6\n"
+	want, err := runParser(input)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var got []result
+	var first *string
+	shared := false
+	h := ini.Handler{
+		ReuseValues: true,
+		KeyValue: func(loc ini.Location, key string, values []string) error {
+			if first == nil {
+				first = &values[:1][0]
+			} else if &values[:1][0] == first {
+				shared = true
+			}
+			got = append(got, result{loc.Line, "key/value", key, append([]string(nil), values...)})
+			return nil
+		},
+		Section: func(loc ini.Location, name string) error {
+			got = append(got, result{loc.Line, "section", name, nil})
+			return nil
+		},
+	}
+	if err := ini.Parse(strings.NewReader(input), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse with ReuseValues (-want, +got)\n%s", diff)
+	}
+	if !shared {
+		t.Error("Values storage was not reused")
+	}
+}
+
+func TestInternNames(t *testing.T) {
+	const input = "[s]\nname = a\n[t]\nname = b\n[s]\nname = c\nbare\nbare\n"
+	parse := func(intern bool) map[string][]string {
+		ptrs := make(map[string][]string)
+		record := func(s string) {
+			ptrs[s] = append(ptrs[s], fmt.Sprint(unsafe.StringData(s)))
+		}
+		h := ini.Handler{
+			InternNames: intern,
+			Section:     func(_ ini.Location, name string) error { record(name); return nil },
+			KeyValue: func(loc ini.Location, key string, _ []string) error {
+				record(key)
+				return nil
+			},
+		}
+		if err := ini.Parse(strings.NewReader(input), h); err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		return ptrs
+	}
+	distinct := func(ss []string) int {
+		m := make(map[string]bool)
+		for _, s := range ss {
+			m[s] = true
+		}
+		return len(m)
+	}
+
+	for name, ps := range parse(true) {
+		if n := distinct(ps); n != 1 {
+			t.Errorf("Interned %q: got %d copies, want 1", name, n)
+		}
+	}
+	if n := distinct(parse(false)["name"]); n != 3 {
+		t.Errorf("Not interned: got %d copies of name, want 3", n)
+	}
+}
+
+func TestMaxLineSize(t *testing.T) {
+	long := strings.Repeat("x", 100000)
+	h := ini.Handler{MaxLineSize: 1 << 20}
+	got, err := runParserWith(h, "k = "+long+"\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Values[0] != long {
+		t.Errorf("Parse: got %d results, want the long value", len(got))
+	}
+
+	h = ini.Handler{BufferSize: 4, MaxLineSize: 10}
+	if _, err := runParserWith(h, "k = 12345\n"); err != nil {
+		t.Errorf("Parse at the limit failed: %v", err)
+	}
+	if _, err := runParserWith(h, "k = 123456\n"); !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("Parse over the limit: got %v, want %v", err, bufio.ErrTooLong)
+	}
+}
+
+func TestOrdinals(t *testing.T) {
+	const input = "top = 1\n[a]\nx = 1\ny\n[b]\n[a]\nz = 2\n"
+	var got []ini.Event
+	h := ini.EventHandler(func(e ini.Event) error {
+		got = append(got, e)
+		return nil
+	})
+	h.Ordinals = true
+	if err := ini.Parse(strings.NewReader(input), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	type ord struct {
+		Name         string
+		Section, Key int
+	}
+	var ords []ord
+	for _, e := range got {
+		ords = append(ords, ord{e.Name, e.SectionOrdinal, e.KeyOrdinal})
+	}
+	want := []ord{
+		{"top", 0, 1},
+		{"a", 1, 0}, {"x", 1, 1}, {"y", 1, 2},
+		{"b", 2, 0},
+		{"a", 3, 0}, {"z", 3, 1},
+	}
+	if diff := cmp.Diff(want, ords); diff != "" {
+		t.Errorf("Ordinals (-want, +got)\n%s", diff)
+	}
+
+	// Skipped sections are counted.
+	var entries []ini.Entry
+	h = ini.CollectOrdered(&entries)
+	h.Ordinals = true
+	if err := ini.ParseSections(strings.NewReader(input), []string{"a"}, h); err != nil {
+		t.Fatalf("ParseSections failed: %v", err)
+	}
+	if n := len(entries); n != 3 || entries[2].SectionOrdinal != 3 || entries[2].KeyOrdinal != 1 {
+		t.Errorf("ParseSections: got %+v, want z at section 3, key 1", entries)
+	}
+}
+
+func TestSectionEnd(t *testing.T) {
+	h := ini.Handler{Dialect: ini.Dialect{SectionEnd: true}}
+	got, err := runParserWith(h, `[a]
+x = 1
+[/a]
+y = 2
+[b c]
+z = 3
+[/ b c ]
+[d]
+[end]
+w = 4
+`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "section", "a", nil},
+		{2, "key/value", "x", []string{"1"}},
+		{3, "section", "", nil},
+		{4, "key/value", "y", []string{"2"}},
+		{5, "section", "b c", nil},
+		{6, "key/value", "z", []string{"3"}},
+		{7, "section", "", nil},
+		{8, "section", "d", nil},
+		{9, "section", "", nil},
+		{10, "key/value", "w", []string{"4"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+
+	_, err = runParserWith(h, "[a]\n[/b]\n")
+	if e, ok := err.(*ini.SyntaxError); !ok || e.Desc != msgMismatchedEnd {
+		t.Errorf("Parse mismatched end: got %v, want %q", err, msgMismatchedEnd)
+	}
+}
+
+func TestBraceBlocks(t *testing.T) {
+	h := ini.Handler{Dialect: ini.Dialect{BraceBlocks: true}}
+	got, err := runParserWith(h, `ctrl_interface=/var/run/wpa_supplicant
+network={
+    ssid="home"
+    eap = {
+        method = PEAP
+    }
+    psk="secret"
+}
+update_config=1
+`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "key/value", "ctrl_interface", []string{"/var/run/wpa_supplicant"}},
+		{2, "section", "network", nil},
+		{3, "key/value", "ssid", []string{`"home"`}},
+		{4, "section", "network/eap", nil},
+		{5, "key/value", "method", []string{"PEAP"}},
+		{6, "section", "network", nil},
+		{7, "key/value", "psk", []string{`"secret"`}},
+		{8, "section", "", nil},
+		{9, "key/value", "update_config", []string{"1"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+
+	tests := []struct {
+		input, desc, key string
+		line             int
+	}{
+		{"a={\nb=1\n", msgUnclosedBlock, "a", 1},
+		{"a={\n[s]\n}\n", msgUnclosedBlock, "a", 1},
+		{"a=1\n}\n", msgUnmatchedBlock, "", 2},
+	}
+	for _, test := range tests {
+		_, err := runParserWith(h, test.input)
+		if e, ok := err.(*ini.SyntaxError); !ok || e.Desc != test.desc || e.Key != test.key || e.Line != test.line {
+			t.Errorf("Parse(%q): got %v, want line %d: %s", test.input, err, test.line, test.desc)
+		}
+	}
+}
+
+func TestMultilineValues(t *testing.T) {
+	h := ini.Handler{Dialect: ini.Dialect{MultilineValues: true}}
+	got, err := runParserWith(h, `[options]
+install_requires =
+
+    requests >= 2.0
+
+    click
+packages = find:
+[testenv]
+commands = pytest
+  flake8 --select=E,W
+
+deps = a
+`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "section", "options", nil},
+		{2, "key/value", "install_requires", []string{"\n\nrequests >= 2.0\n\nclick"}},
+		{7, "key/value", "packages", []string{"find:"}},
+		{8, "section", "testenv", nil},
+		{9, "key/value", "commands", []string{"pytest\nflake8 --select=E,W"}},
+		{12, "key/value", "deps", []string{"a"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+}
+
+// As in Python's configparser, a value whose key line is empty begins with a
+// newline, so that a consumer can tell it apart from a value given on the key
+// line and continued.
+func TestMultilineLeadingNewline(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"k =\n", ""},
+		{"k =\n  a\n", "\na"},
+		{"k =\n  a = 1\n  b = 2\n", "\na = 1\nb = 2"},
+		{"k = x\n  a\n", "x\na"},
+		{"k = x\n\n  a\n", "x\n\na"},
+	}
+	h := ini.Handler{Dialect: ini.Dialect{MultilineValues: true}}
+	for _, test := range tests {
+		got, err := runParserWith(h, test.input)
+		if err != nil {
+			t.Fatalf("Parse %q failed: %v", test.input, err)
+		}
+		want := []result{{1, "key/value", "k", []string{test.want}}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Parse %q (-want, +got)\n%s", test.input, diff)
+		}
+	}
+}
+
+func TestFlake8(t *testing.T) {
+	h := ini.Handler{Dialect: ini.Flake8}
+	got, err := runParserWith(h, `[flake8]
+extend-ignore =
+    # black compatibility
+    E203,
+
+    W503, E501
+max-line-length = 88
+exclude = ,
+`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "section", "flake8", nil},
+		{3, "comment", "", nil},
+		{2, "key/value", "extend-ignore", []string{"E203", "W503", "E501"}},
+		{7, "key/value", "max-line-length", []string{"88"}},
+		{8, "key/value", "exclude", []string{""}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+}
+
+func TestInnoSetup(t *testing.T) {
+	h := ini.Handler{Dialect: ini.InnoSetup}
+	got, err := runParserWith(h, `[Messages]
+WelcomeLabel2=This will install [name].%n%nIt is 100%% free.
+Padded="  spaced  "
+Half="open
+Args=Copy %1 to %2%%n
+`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "section", "Messages", nil},
+		{2, "key/value", "WelcomeLabel2", []string{"This will install [name].\n\nIt is 100% free."}},
+		{3, "key/value", "Padded", []string{"  spaced  "}},
+		{4, "key/value", "Half", []string{`"open`}},
+		{5, "key/value", "Args", []string{"Copy %1 to %2%n"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+}
+
+func TestNestedSections(t *testing.T) {
+	h := ini.Handler{Dialect: ini.Dialect{NestedSections: true}}
+	got, err := runParserWith(h, `[parent]
+a = 1
+  [child]
+  b = 2
+    [grandchild]
+  [sibling]
+[other]
+ [sub]
+`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "section", "parent", nil},
+		{2, "key/value", "a", []string{"1"}},
+		{3, "section", "parent/child", nil},
+		{4, "key/value", "b", []string{"2"}},
+		{5, "section", "parent/child/grandchild", nil},
+		{6, "section", "parent/sibling", nil},
+		{7, "section", "other", nil},
+		{8, "section", "other/sub", nil},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+}
+
+func TestAttachComments(t *testing.T) {
+	const input = `; file header
+
+; about s
+[s]
+; about a
+;   more
+a = 1
+  2
+b
+; trailing
+`
+	var got []ini.Event
+	h := ini.EventHandler(func(e ini.Event) error {
+		got = append(got, e)
+		return nil
+	})
+	h.AttachComments = true
+	if err := ini.Parse(strings.NewReader(input), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []ini.Event{
+		{Kind: ini.KindSection, Line: 4, Name: "s", Comments: []string{"; file header", "; about s"}},
+		{Kind: ini.KindKey, Line: 7, Section: "s", Name: "a", Values: []string{"1", "2"},
+			Comments: []string{"; about a", ";   more"}},
+		{Kind: ini.KindKey, Line: 9, Section: "s", Name: "b", Values: []string{""}},
+		{Kind: ini.KindComment, Line: 10, Section: "s", Text: "; trailing"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+}
+
+func TestParseSections(t *testing.T) {
+	const input = `a = 1
+[x]
+b = 2
+ 3
+[y]
+; skipped
+c = 4
+[z]
+; kept
+d = 5
+[y]
+e
+`
+	tests := []struct {
+		names []string
+		want  []result
+	}{
+		{nil, nil},
+		{[]string{""}, []result{{1, "key/value", "a", []string{"1"}}}},
+		{[]string{"x", "z"}, []result{
+			{2, "section", "x", nil},
+			{3, "key/value", "b", []string{"2", "3"}},
+			{8, "section", "z", nil},
+			{9, "comment", "", nil},
+			{10, "key/value", "d", []string{"5"}},
+		}},
+		{[]string{"y"}, []result{
+			{5, "section", "y", nil},
+			{6, "comment", "", nil},
+			{7, "key/value", "c", []string{"4"}},
+			{11, "section", "y", nil},
+			{12, "key/value", "e", []string{""}},
+		}},
+	}
+	for _, test := range tests {
+		var got []result
+		err := ini.ParseSections(strings.NewReader(input), test.names, recordTo(&got, ini.Handler{}))
+		if err != nil {
+			t.Errorf("ParseSections %q failed: %v", test.names, err)
+		} else if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseSections %q (-want, +got)\n%s", test.names, diff)
+		}
+	}
+}
+
+func TestParseMatching(t *testing.T) {
+	const input = `region = us-east-1
+[default]
+output = json
+[profile dev]
+region = us-west-2
+[profile prod]
+; production
+region = eu-west-1
+[sso-session main]
+sso_region = us-east-1
+`
+	tests := []struct {
+		pattern string
+		want    []result
+	}{
+		{"", []result{{1, "key/value", "region", []string{"us-east-1"}}}},
+		{"profile *", []result{
+			{4, "section", "profile dev", nil},
+			{5, "key/value", "region", []string{"us-west-2"}},
+			{6, "section", "profile prod", nil},
+			{7, "comment", "", nil},
+			{8, "key/value", "region", []string{"eu-west-1"}},
+		}},
+		{"[ds]*", []result{
+			{2, "section", "default", nil},
+			{3, "key/value", "output", []string{"json"}},
+			{9, "section", "sso-session main", nil},
+			{10, "key/value", "sso_region", []string{"us-east-1"}},
+		}},
+		{"none", nil},
+	}
+	for _, test := range tests {
+		var got []result
+		err := ini.ParseMatching(strings.NewReader(input), test.pattern, recordTo(&got, ini.Handler{}))
+		if err != nil {
+			t.Errorf("ParseMatching %q failed: %v", test.pattern, err)
+		} else if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseMatching %q (-want, +got)\n%s", test.pattern, diff)
+		}
+	}
+
+	if err := ini.ParseMatching(strings.NewReader(input), "[bad", ini.Handler{}); err == nil {
+		t.Error("ParseMatching with bad pattern: got nil, want error")
+	}
+}
+
 // These must be in sync with the package ini values.
 const (
 	msgUnclosedHeader = "unclosed section header"
 	msgInvalidSection = "invalid section name"
 	msgEmptyKey       = "empty key"
+	msgMismatchedEnd  = "mismatched section end"
+	msgUnclosedBlock  = "unclosed block"
+	msgUnmatchedBlock = "unmatched block end"
+	msgInvalidKey     = "invalid key name"
+	msgInvalidUTF8    = "invalid UTF-8"
+	msgInvalidInclude = "invalid include pattern"
+	msgIncludeOpen    = "cannot open included file"
 )
 
 func TestParseErrors(t *testing.T) {
@@ -182,6 +1234,8 @@ func TestParseErrors(t *testing.T) {
 			t.Errorf("Parse(%q): got %+v, want error", test.input, got)
 		} else if e, ok := err.(*ini.SyntaxError); !ok {
 			t.Errorf("Parse(%q): got unexpected error: %v", test.input, err)
+		} else if !errors.Is(err, ini.ErrSyntax) || errors.Is(err, ini.ErrValidation) {
+			t.Errorf("Parse(%q): error %v has the wrong category", test.input, err)
 		} else if e.Desc != test.desc || e.Key != test.key {
 			t.Errorf("Parse(%q): got error (%q, %q), want (%q, %q)",
 				test.input, e.Desc, e.Key, test.desc, test.key)
@@ -189,6 +1243,249 @@ func TestParseErrors(t *testing.T) {
 	}
 }
 
+func TestIOError(t *testing.T) {
+	fail := errors.New("disk on fire")
+	r := io.MultiReader(strings.NewReader("a = 1\nb = 2\n"), iotest.ErrReader(fail))
+	err := ini.Parse(r, ini.Handler{})
+	var ioerr *ini.IOError
+	if !errors.As(err, &ioerr) {
+		t.Fatalf("Parse: got %v, want *IOError", err)
+	}
+	if !errors.Is(err, ini.ErrIO) || !errors.Is(err, fail) || errors.Is(err, ini.ErrSyntax) {
+		t.Errorf("Parse: error %v has the wrong category", err)
+	}
+	if got, want := err.Error(), "line 3: read error: disk on fire"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+
+	h := ini.Handler{Dialect: ini.Dialect{IncludeKey: "include"}}
+	err = ini.Parse(strings.NewReader("include = testdata/nonesuch.ini\n"), h)
+	if !errors.Is(err, ini.ErrIO) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Parse include: got %v, want a not-exist I/O error", err)
+	}
+	if loc := ini.ErrorLocation(err); loc.Line != 1 {
+		t.Errorf("ErrorLocation: got line %d, want 1", loc.Line)
+	}
+}
+
+func TestParseLines(t *testing.T) {
+	lines := []string{"a = 1", "  2", "[s]", "b = 3"}
+	var got []ini.Entry
+	start := ini.Location{Line: 10, Section: "outer", File: "template.ini"}
+	if err := ini.ParseLines(lines, start, ini.CollectOrdered(&got)); err != nil {
+		t.Fatalf("ParseLines failed: %v", err)
+	}
+	want := []ini.Entry{
+		{Location: ini.Location{Line: 10, Section: "outer", File: "template.ini"}, Key: "a", Values: []string{"1", "2"}},
+		{Location: ini.Location{Line: 13, Section: "s", File: "template.ini"}, Key: "b", Values: []string{"3"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ParseLines (-want, +got)\n%s", diff)
+	}
+
+	err := ini.ParseLines([]string{"ok = 1", "[bad"}, ini.Location{}, ini.Handler{})
+	if loc := ini.ErrorLocation(err); loc.Line != 2 {
+		t.Errorf("ParseLines: got %v, want error at line 2", err)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	input := strings.Repeat("k = v\n", 25) + "last = 1"
+	type report struct {
+		Bytes int64
+		Lines int
+	}
+	var got []report
+	h := ini.Handler{
+		ProgressLines: 10,
+		Progress: func(bytes int64, lines int) error {
+			got = append(got, report{bytes, lines})
+			return nil
+		},
+	}
+	if err := ini.Parse(strings.NewReader(input), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []report{{60, 10}, {120, 20}, {158, 26}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Progress reports (-want, +got)\n%s", diff)
+	}
+
+	errStop := errors.New("stop")
+	h.Progress = func(int64, int) error { return errStop }
+	if err := ini.Parse(strings.NewReader(input), h); err != errStop {
+		t.Errorf("Parse: got %v, want %v", err, errStop)
+	}
+}
+
+func TestHandlerPanic(t *testing.T) {
+	errBoom := errors.New("boom")
+	h := ini.Handler{
+		KeyValue: func(loc ini.Location, key string, values []string) error {
+			if key == "bad" {
+				panic(errBoom)
+			}
+			return nil
+		},
+		Section: func(ini.Location, string) error { panic("section") },
+	}
+	err := ini.Parse(strings.NewReader("ok = 1\nbad = 2\n"), h)
+	if e, ok := err.(*ini.HandlerError); !ok || e.Line != 2 || e.Value != errBoom {
+		t.Errorf("Parse: got %v, want handler error at line 2", err)
+	} else if !errors.Is(err, errBoom) {
+		t.Errorf("Parse: got %v, want %v", err, errBoom)
+	}
+	err = ini.Parse(strings.NewReader("[s]\n"), h)
+	if e, ok := err.(*ini.HandlerError); !ok || e.Line != 1 || e.Value != "section" {
+		t.Errorf("Parse: got %v, want handler error at line 1", err)
+	}
+
+	h.PropagatePanics = true
+	func() {
+		defer func() {
+			if v := recover(); v != errBoom {
+				t.Errorf("Parse with PropagatePanics: got panic %v, want %v", v, errBoom)
+			}
+		}()
+		ini.Parse(strings.NewReader("bad = 1\n"), h)
+	}()
+}
+
+func TestReadDialect(t *testing.T) {
+	const desc = `; An in-house format.
+hashcomments = yes
+FoldKeys = on
+IncludeKey = include
+
+[Synonyms]
+colour = color
+`
+	d, err := ini.ReadDialect(strings.NewReader(desc))
+	if err != nil {
+		t.Fatalf("ReadDialect failed: %v", err)
+	}
+	want := ini.Dialect{
+		HashComments: true,
+		FoldKeys:     true,
+		IncludeKey:   "include",
+		Synonyms:     map[string]string{"colour": "color"},
+	}
+	if diff := cmp.Diff(want, d); diff != "" {
+		t.Errorf("ReadDialect (-want, +got)\n%s", diff)
+	}
+
+	// A Dialect survives a round trip through JSON.
+	data, err := json.Marshal(ini.Samba)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var samba ini.Dialect
+	if err := json.Unmarshal(data, &samba); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if diff := cmp.Diff(ini.Samba, samba); diff != "" {
+		t.Errorf("JSON round trip (-want, +got)\n%s", diff)
+	}
+
+	for _, bad := range []string{
+		"NoSuchField = true\n",
+		"HashComments = maybe\n",
+		"Synonyms = x\n",
+		"[HashComments]\n",
+		"[Other]\n",
+	} {
+		d, err := ini.ReadDialect(strings.NewReader(bad))
+		var se *ini.SyntaxError
+		if !errors.As(err, &se) || se.Line != 1 {
+			t.Errorf("ReadDialect(%q): got %+v, %v, want syntax error at line 1", bad, d, err)
+		}
+	}
+}
+
+func TestContinueOnError(t *testing.T) {
+	errBad := errors.New("bad record")
+	var keys []string
+	h := ini.Handler{
+		ContinueOnError: true,
+		KeyValue: func(loc ini.Location, key string, values []string) error {
+			if values[0] == "bad" {
+				return errBad
+			}
+			keys = append(keys, key)
+			return nil
+		},
+	}
+	err := ini.Parse(strings.NewReader("a = ok\nb = bad\nc = ok\nd = bad\n"), h)
+	errs, ok := err.(*ini.ErrorList)
+	if !ok {
+		t.Fatalf("Parse: got %v, want *ini.ErrorList", err)
+	}
+	if diff := cmp.Diff([]string{"a", "c"}, keys); diff != "" {
+		t.Errorf("Keys (-want, +got)\n%s", diff)
+	}
+	if len(errs.Errors) != 2 {
+		t.Fatalf("Parse: got %d errors, want 2: %v", len(errs.Errors), err)
+	}
+	for i, line := range []int{2, 4} {
+		e, ok := errs.Errors[i].(*ini.CallbackError)
+		if !ok || e.Line != line || e.Err != errBad {
+			t.Errorf("Error %d: got %v, want line %d: %v", i, errs.Errors[i], line, errBad)
+		} else if loc := ini.ErrorLocation(e); loc.Line != line {
+			t.Errorf("ErrorLocation: got %+v, want line %d", loc, line)
+		}
+	}
+	if !errors.Is(err, errBad) {
+		t.Errorf("Parse: got %v, want %v", err, errBad)
+	}
+
+	// An error that stops parsing follows the recorded errors.
+	err = ini.Parse(strings.NewReader("a = bad\n; end\n[broken\n"), h)
+	if errs, ok := err.(*ini.ErrorList); !ok || len(errs.Errors) != 2 {
+		t.Errorf("Parse: got %v, want 2 errors", err)
+	} else if !errors.Is(errs.Errors[1], ini.ErrSyntax) {
+		t.Errorf("Parse: got %v, want a syntax error last", errs.Errors[1])
+	}
+
+	// Without callback errors, errors are reported as usual.
+	if err := ini.Parse(strings.NewReader("[broken\n"), h); !errors.Is(err, ini.ErrSyntax) {
+		t.Errorf("Parse: got %v, want a syntax error", err)
+	} else if _, ok := err.(*ini.ErrorList); ok {
+		t.Errorf("Parse: got an ErrorList %v, want a single error", err)
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	var sb strings.Builder
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&sb, "; section %d\n[section %d]\n", i, i)
+		for j := 0; j < 10; j++ {
+			fmt.Fprintf(&sb, "  key %d = value %d, with some text  \n", j, i*j)
+		}
+		sb.WriteString("list = first\n    second\n    third\n\n")
+	}
+	input := sb.String()
+	for _, bm := range []struct {
+		name string
+		h    ini.Handler
+	}{
+		{"Default", ini.Handler{}},
+		{"ASCIISpace", ini.Handler{Dialect: ini.Dialect{ASCIISpace: true}}},
+		{"ReuseValues", ini.Handler{ReuseValues: true}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			h := bm.h
+			h.KeyValue = func(ini.Location, string, []string) error { return nil }
+			b.SetBytes(int64(len(input)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := ini.Parse(strings.NewReader(input), h); err != nil {
+					b.Fatalf("Parse failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
 func ExampleParse() {
 	const iniFile = `
 ;
@@ -0,0 +1,151 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package initest provides support for golden-file testing of INI parsers
+// and handlers built on package ini.
+//
+// A test corpus is a directory of input files with the suffix ".ini". Each
+// input has a corresponding file with the suffix ".ndjson" giving the events
+// expected from parsing it, in the format written by ini.JSONHandler. If the
+// input is expected to fail, a file with the suffix ".err" instead gives the
+// expected error text.
+package initest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+)
+
+// A ParseFunc parses INI data from r and invokes the callbacks on h. The
+// ini.Parse function is a ParseFunc.
+type ParseFunc func(r io.Reader, h ini.Handler) error
+
+// Events parses r with parse and returns the resulting events.
+func Events(r io.Reader, parse ParseFunc) ([]ini.Event, error) {
+	var out []ini.Event
+	err := parse(r, ini.EventHandler(func(e ini.Event) error {
+		out = append(out, e)
+		return nil
+	}))
+	return out, err
+}
+
+// ReadEvents reads a stream of JSON-encoded events from r, as written by
+// ini.JSONHandler.
+func ReadEvents(r io.Reader) ([]ini.Event, error) {
+	var out []ini.Event
+	dec := json.NewDecoder(r)
+	for {
+		var e ini.Event
+		if err := dec.Decode(&e); err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+}
+
+// RunCorpus parses each input file in the corpus directory dir with parse,
+// and checks the results against the expected events or error. Each input is
+// checked in a separate subtest of t named by its base name. RunCorpus fails
+// t if dir contains no input files.
+func RunCorpus(t *testing.T, dir string, parse ParseFunc) {
+	t.Helper()
+	inputs, err := filepath.Glob(filepath.Join(dir, "*.ini"))
+	if err != nil {
+		t.Fatalf("Listing corpus: %v", err)
+	} else if len(inputs) == 0 {
+		t.Fatalf("No input files found in %q", dir)
+	}
+	for _, path := range inputs {
+		base := strings.TrimSuffix(path, ".ini")
+		t.Run(filepath.Base(base), func(t *testing.T) {
+			input, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Reading input: %v", err)
+			}
+			got, perr := Events(bytes.NewReader(input), parse)
+
+			if want, err := os.ReadFile(base + ".err"); err == nil {
+				if perr == nil {
+					t.Fatalf("Parse: got %d events, want error %q", len(got), want)
+				} else if w := strings.TrimSpace(string(want)); perr.Error() != w {
+					t.Errorf("Parse: got error %q, want %q", perr.Error(), w)
+				}
+				return
+			} else if perr != nil {
+				t.Fatalf("Parse: unexpected error: %v", perr)
+			}
+
+			f, err := os.Open(base + ".ndjson")
+			if err != nil {
+				t.Fatalf("Reading expected events: %v", err)
+			}
+			defer f.Close()
+			want, err := ReadEvents(f)
+			if err != nil {
+				t.Fatalf("Decoding expected events: %v", err)
+			}
+			if diff := DiffEvents(want, got); diff != "" {
+				t.Errorf("Events (-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+// DiffEvents returns a description of the differences between want and got,
+// or "" if they are equal. Each event that differs is listed in the JSON
+// format written by ini.JSONHandler, prefixed by "-" if it is from want and
+// "+" if it is from got.
+func DiffEvents(want, got []ini.Event) string {
+	var buf strings.Builder
+	for i := 0; i < len(want) || i < len(got); i++ {
+		var w, g *ini.Event
+		if i < len(want) {
+			w = &want[i]
+		}
+		if i < len(got) {
+			g = &got[i]
+		}
+		if w != nil && g != nil && reflect.DeepEqual(*w, *g) {
+			continue
+		}
+		fmt.Fprintf(&buf, "event %d:\n", i+1)
+		if w != nil {
+			fmt.Fprintf(&buf, "- %s\n", eventJSON(*w))
+		}
+		if g != nil {
+			fmt.Fprintf(&buf, "+ %s\n", eventJSON(*g))
+		}
+	}
+	return buf.String()
+}
+
+func eventJSON(e ini.Event) string {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Sprintf("%+v", e)
+	}
+	return string(data)
+}
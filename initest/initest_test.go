@@ -0,0 +1,43 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package initest_test
+
+import (
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/creachadair/ini/initest"
+)
+
+func TestCorpus(t *testing.T) {
+	initest.RunCorpus(t, "testdata", ini.Parse)
+}
+
+func TestDiffEvents(t *testing.T) {
+	a := []ini.Event{{Kind: ini.KindSection, Line: 1, Name: "s"}, {Kind: ini.KindKey, Line: 2, Name: "k"}}
+	if diff := initest.DiffEvents(a, a); diff != "" {
+		t.Errorf("DiffEvents(a, a): got %q, want empty", diff)
+	}
+	b := []ini.Event{a[0], {Kind: ini.KindKey, Line: 2, Name: "x"}, {Kind: ini.KindComment, Line: 3}}
+	const want = `event 2:
+- {"kind":"key","line":2,"name":"k"}
++ {"kind":"key","line":2,"name":"x"}
+event 3:
++ {"kind":"comment","line":3}
+`
+	if got := initest.DiffEvents(a, b); got != want {
+		t.Errorf("DiffEvents(a, b): got\n%s\nwant\n%s", got, want)
+	}
+}
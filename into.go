@@ -0,0 +1,51 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import "io"
+
+// HandlerFuncs is a set of callbacks like those of a Handler, each of which
+// also receives a pointer to a value of type T. This allows a single set of
+// callbacks to accumulate results into different values.
+type HandlerFuncs[T any] struct {
+	Comment  func(v *T, loc Location, text string) error
+	Section  func(v *T, loc Location, name string) error
+	KeyValue func(v *T, loc Location, key string, values []string) error
+}
+
+// Bind returns a Handler whose callbacks invoke the corresponding callbacks
+// of h with v. Callbacks that are nil in h are nil in the result.
+func (h HandlerFuncs[T]) Bind(v *T) Handler {
+	var out Handler
+	if h.Comment != nil {
+		out.Comment = func(loc Location, text string) error { return h.Comment(v, loc, text) }
+	}
+	if h.Section != nil {
+		out.Section = func(loc Location, name string) error { return h.Section(v, loc, name) }
+	}
+	if h.KeyValue != nil {
+		out.KeyValue = func(loc Location, key string, values []string) error {
+			return h.KeyValue(v, loc, key, values)
+		}
+	}
+	return out
+}
+
+// ParseInto parses the INI data from r as Parse, invoking the callbacks of h
+// with v. To use other options of Handler, set them on the result of Bind
+// and call Parse directly.
+func ParseInto[T any](r io.Reader, v *T, h HandlerFuncs[T]) error {
+	return Parse(r, h.Bind(v))
+}
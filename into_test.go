@@ -0,0 +1,62 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+type counts struct {
+	Comments, Sections int
+	Keys               []string
+}
+
+var countFuncs = ini.HandlerFuncs[counts]{
+	Comment: func(c *counts, _ ini.Location, _ string) error {
+		c.Comments++
+		return nil
+	},
+	Section: func(c *counts, _ ini.Location, _ string) error {
+		c.Sections++
+		return nil
+	},
+	KeyValue: func(c *counts, loc ini.Location, key string, _ []string) error {
+		c.Keys = append(c.Keys, loc.Section+"."+key)
+		return nil
+	},
+}
+
+func TestParseInto(t *testing.T) {
+	tests := []struct {
+		input string
+		want  counts
+	}{
+		{"", counts{}},
+		{"; a\n[s]\nk=v\n; b\n", counts{Comments: 2, Sections: 1, Keys: []string{"s.k"}}},
+		{"x\n[t]\n[u]\ny=1\n z", counts{Sections: 2, Keys: []string{".x", "u.y"}}},
+	}
+	for _, test := range tests {
+		var got counts
+		if err := ini.ParseInto(strings.NewReader(test.input), &got, countFuncs); err != nil {
+			t.Errorf("ParseInto(%q) failed: %v", test.input, err)
+		} else if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseInto(%q) (-want, +got)\n%s", test.input, diff)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// A Diagnostic describes a potential problem found in INI input.
+type Diagnostic struct {
+	Location
+	Rule    string // the name of the rule reporting the problem
+	Message string // a human-readable description of the problem
+
+	// If not empty, Fix is a set of non-overlapping edits to the input that
+	// correct the problem. Use ApplyFixes to apply them.
+	Fix []Edit
+}
+
+// An Edit describes the replacement of a span of bytes in the input.
+type Edit struct {
+	Start int64  // the byte offset of the start of the span
+	End   int64  // the byte offset just past the end of the span
+	Text  string // the replacement text
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("line %d: %s [%s]", d.Line, d.Message, d.Rule)
+}
+
+// Names of lint rules reported in diagnostics.
+const (
+	// RuleAmbiguousIndent reports continuation lines whose indentation is easy
+	// to misread: a single space, a mixture of tabs and spaces, or a different
+	// indentation than the preceding continuation lines of the same key.
+	RuleAmbiguousIndent = "ambiguous-indent"
+
+	// RuleSchema reports input that does not conform to a schema; see
+	// Conform.
+	RuleSchema = "schema"
+
+	// RuleSyntax reports input that could not be read or is not valid INI
+	// data; see Conform.
+	RuleSyntax = "syntax"
+)
+
+// Lint scans the INI data from r and reports potential problems that are not
+// syntax errors. The diagnostics are reported in order of occurrence. If r is
+// not valid INI data, Lint reports the diagnostics found before the syntax
+// error, along with the error. Lint uses the default syntax; it is equivalent
+// to Dialect{}.Lint(r).
+func Lint(r io.Reader) ([]Diagnostic, error) { return Dialect{}.Lint(r) }
+
+// Lint behaves as the Lint function, but parses r using the syntax of d. If
+// d has an IncludeKey, included files are checked too. Diagnostics for an
+// included file report its path in the File field of their Location, and
+// the offsets of their fixes are relative to the start of that file, so the
+// fixes for each file must be applied separately.
+func (d Dialect) Lint(r io.Reader) ([]Diagnostic, error) {
+	var out []Diagnostic
+	var lastKey Location // the location of the key most recently continued
+	var keyIndent string // the indentation to use for its continuations
+	err := parse(r, Handler{Dialect: d}, parseConfig{
+		cont: func(loc, keyLoc Location, key, text string, offset int64) {
+			indent := d.indent(text)
+			ambiguous := indent == " " || (strings.Contains(indent, " ") && strings.Contains(indent, "\t"))
+			if keyLoc.Line != lastKey.Line || keyLoc.File != lastKey.File {
+				lastKey, keyIndent = keyLoc, indent
+				if ambiguous {
+					keyIndent = "  "
+				}
+			}
+
+			var msg string
+			switch {
+			case indent == " ":
+				msg = "continuation indented by a single space"
+			case ambiguous:
+				msg = "continuation indented by a mixture of tabs and spaces"
+			case indent != keyIndent:
+				msg = fmt.Sprintf("continuation indented differently than other values of %q", key)
+			default:
+				return
+			}
+			out = append(out, Diagnostic{
+				Location: loc,
+				Rule:     RuleAmbiguousIndent,
+				Message:  msg,
+				Fix: []Edit{{
+					Start: offset,
+					End:   offset + int64(len(indent)),
+					Text:  keyIndent,
+				}},
+			})
+		},
+	})
+	return out, err
+}
+
+// ApplyFixes applies the fixes from ds to src and returns the resulting text.
+// Bytes of src not covered by an edit are copied unchanged. If the fix for a
+// diagnostic overlaps an edit from a diagnostic earlier in ds, none of its
+// edits are applied, and it is included in the returned slice of skipped
+// diagnostics. Diagnostics without a fix are ignored.
+func ApplyFixes(src []byte, ds []Diagnostic) ([]byte, []Diagnostic) {
+	var edits []Edit
+	var skipped []Diagnostic
+	for _, d := range ds {
+		if len(d.Fix) == 0 {
+			continue
+		} else if !fixApplies(d.Fix, edits, int64(len(src))) {
+			skipped = append(skipped, d)
+			continue
+		}
+		edits = append(edits, d.Fix...)
+	}
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].Start < edits[j].Start })
+
+	var buf bytes.Buffer
+	var last int64
+	for _, e := range edits {
+		buf.Write(src[last:e.Start])
+		buf.WriteString(e.Text)
+		last = e.End
+	}
+	buf.Write(src[last:])
+	return buf.Bytes(), skipped
+}
+
+// fixApplies reports whether the edits of fix are valid spans of an input of
+// length n, and do not overlap each other or any of the accepted edits.
+func fixApplies(fix, accepted []Edit, n int64) bool {
+	for i, e := range fix {
+		if e.Start < 0 || e.End < e.Start || e.End > n {
+			return false
+		}
+		if overlaps(e, accepted) || overlaps(e, fix[:i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps reports whether e overlaps any of the edits in es.
+func overlaps(e Edit, es []Edit) bool {
+	for _, o := range es {
+		if e.Start < o.End && o.Start < e.End {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+const lintInput = "a =\n  ok\n  fine\nb = x\n single\n\t \tmixed\n[s]\nc =\n\tone\n  two\nd = 1\n  e = 2\n"
+
+func TestLintIndent(t *testing.T) {
+	got, err := ini.Lint(strings.NewReader(lintInput))
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	want := []ini.Diagnostic{
+		{Location: ini.Location{Line: 5}, Rule: ini.RuleAmbiguousIndent,
+			Message: "continuation indented by a single space",
+			Fix:     []ini.Edit{{Start: 22, End: 23, Text: "  "}}},
+		{Location: ini.Location{Line: 6}, Rule: ini.RuleAmbiguousIndent,
+			Message: "continuation indented by a mixture of tabs and spaces",
+			Fix:     []ini.Edit{{Start: 30, End: 33, Text: "  "}}},
+		{Location: ini.Location{Line: 10, Section: "s"}, Rule: ini.RuleAmbiguousIndent,
+			Message: `continuation indented differently than other values of "c"`,
+			Fix:     []ini.Edit{{Start: 52, End: 54, Text: "\t"}}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Lint (-want, +got)\n%s", diff)
+	}
+
+	if _, err := ini.Lint(strings.NewReader("[bad")); err == nil {
+		t.Error("Lint: got nil, want error")
+	}
+}
+
+func TestApplyFixes(t *testing.T) {
+	ds, err := ini.Lint(strings.NewReader(lintInput))
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	conflict := ini.Diagnostic{Rule: "test", Fix: []ini.Edit{{Start: 21, End: 24, Text: "!"}}}
+	ds = append(ds, conflict, ini.Diagnostic{Rule: "nofix"})
+
+	got, skipped := ini.ApplyFixes([]byte(lintInput), ds)
+	const want = "a =\n  ok\n  fine\nb = x\n  single\n  mixed\n[s]\nc =\n\tone\n\ttwo\nd = 1\n  e = 2\n"
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("ApplyFixes (-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]ini.Diagnostic{conflict}, skipped); diff != "" {
+		t.Errorf("Skipped (-want, +got)\n%s", diff)
+	}
+
+	// The fixed text should have no further complaints.
+	if ds, err := ini.Lint(strings.NewReader(string(got))); err != nil {
+		t.Errorf("Lint failed: %v", err)
+	} else if len(ds) != 0 {
+		t.Errorf("Lint after fixes: got %+v, want none", ds)
+	}
+}
+
+func TestLintIncludes(t *testing.T) {
+	dir := t.TempDir()
+	inc := filepath.Join(dir, "inc.conf")
+	if err := os.WriteFile(inc, []byte("[sub]\nk =\n a\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	input := "x =\n  ok\nInclude = " + inc + "\ny =\n\t b\n"
+
+	last := int64(strings.LastIndex(input, "\t"))
+	got, err := ini.Pacman.Lint(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	want := []ini.Diagnostic{
+		{Location: ini.Location{Line: 3, Section: "sub", File: inc}, Rule: ini.RuleAmbiguousIndent,
+			Message: "continuation indented by a single space",
+			Fix:     []ini.Edit{{Start: 10, End: 11, Text: "  "}}},
+		{Location: ini.Location{Line: 5, Section: "sub"}, Rule: ini.RuleAmbiguousIndent,
+			Message: "continuation indented by a mixture of tabs and spaces",
+			Fix:     []ini.Edit{{Start: last, End: last + 2, Text: "  "}}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Lint (-want, +got)\n%s", diff)
+	}
+}
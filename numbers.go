@@ -0,0 +1,142 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// A NumberFormat describes the punctuation of decimal numbers in a locale.
+// The zero value permits no digit grouping, reads integers in decimal, so
+// that "010" is ten, and reads floats as strconv.ParseFloat does. It is the
+// default for both Schema and Unmarshaler, so that a file that validates
+// against a schema decodes to the same values.
+type NumberFormat struct {
+	Decimal rune // the decimal separator, or 0 for '.'
+	Group   rune // the digit group separator, or 0 for none
+}
+
+var (
+	// PeriodDecimal uses a period as the decimal separator and a comma
+	// between digit groups, for example "1,234.56".
+	PeriodDecimal = NumberFormat{Decimal: '.', Group: ','}
+
+	// CommaDecimal uses a comma as the decimal separator and a period
+	// between digit groups, for example "1.234,56".
+	CommaDecimal = NumberFormat{Decimal: ',', Group: '.'}
+
+	// SpaceGrouped uses a comma as the decimal separator and a space between
+	// digit groups, for example "1 234,56". A no-break space or narrow
+	// no-break space is also accepted between groups.
+	SpaceGrouped = NumberFormat{Decimal: ',', Group: ' '}
+)
+
+// localeFormats maps language tags to number formats.
+var localeFormats = map[string]NumberFormat{
+	"en": PeriodDecimal, "ja": PeriodDecimal, "ko": PeriodDecimal, "zh": PeriodDecimal,
+	"de": CommaDecimal, "es": CommaDecimal, "it": CommaDecimal, "nl": CommaDecimal,
+	"pt": CommaDecimal, "da": CommaDecimal, "id": CommaDecimal, "tr": CommaDecimal,
+	"fr": SpaceGrouped, "ru": SpaceGrouped, "pl": SpaceGrouped, "cs": SpaceGrouped,
+	"sv": SpaceGrouped, "fi": SpaceGrouped, "nb": SpaceGrouped, "uk": SpaceGrouped,
+}
+
+// NumberFormatFor returns the number format for the given locale, such as
+// "de" or "fr_FR.UTF-8". Only the language portion of the locale is
+// considered. It reports false if the language is not known.
+func NumberFormatFor(locale string) (NumberFormat, bool) {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "-_."); i >= 0 {
+		lang = lang[:i]
+	}
+	f, ok := localeFormats[lang]
+	return f, ok
+}
+
+// ParseInt parses s as a signed integer in format f.
+func (f NumberFormat) ParseInt(s string) (int64, error) {
+	if f == (NumberFormat{}) {
+		return strconv.ParseInt(s, 10, 64)
+	}
+	norm, ok := f.normalize(s, false)
+	if !ok {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return strconv.ParseInt(norm, 10, 64)
+}
+
+// ParseFloat parses s as a floating-point number in format f.
+func (f NumberFormat) ParseFloat(s string) (float64, error) {
+	if f == (NumberFormat{}) {
+		return strconv.ParseFloat(s, 64)
+	}
+	norm, ok := f.normalize(s, true)
+	if !ok {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return strconv.ParseFloat(norm, 64)
+}
+
+// normalize converts s from format f to the syntax of strconv, removing digit
+// group separators and replacing the decimal separator. Group separators are
+// permitted only between groups of three digits in the integer part.
+func (f NumberFormat) normalize(s string, frac bool) (string, bool) {
+	dec := f.Decimal
+	if dec == 0 {
+		dec = '.'
+	}
+	sign := ""
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+	whole, rest := s, ""
+	if i := strings.IndexRune(s, dec); i >= 0 {
+		if !frac {
+			return "", false
+		}
+		whole, rest = s[:i], "."+s[i+utf8.RuneLen(dec):]
+	}
+	if f.Group != 0 {
+		groups := strings.FieldsFunc(whole, f.isGroup)
+		if len(groups) > 1 {
+			if n := len(groups[0]); n == 0 || n > 3 {
+				return "", false
+			}
+			for _, g := range groups[1:] {
+				if len(g) != 3 {
+					return "", false
+				}
+			}
+			if strings.Join(groups, string(f.Group)) != strings.Map(f.mapGroup, whole) {
+				return "", false // empty groups or stray separators
+			}
+		}
+		whole = strings.Join(groups, "")
+	}
+	return sign + whole + rest, whole != "" || rest != ""
+}
+
+func (f NumberFormat) isGroup(r rune) bool {
+	return r == f.Group || (f.Group == ' ' && (r == '\u00a0' || r == '\u202f'))
+}
+
+func (f NumberFormat) mapGroup(r rune) rune {
+	if f.isGroup(r) {
+		return f.Group
+	}
+	return r
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/creachadair/ini"
+)
+
+func TestNumberFormat(t *testing.T) {
+	tests := []struct {
+		f     ini.NumberFormat
+		input string
+		want  float64
+		ok    bool
+	}{
+		{ini.NumberFormat{}, "1234.5", 1234.5, true},
+		{ini.NumberFormat{}, "1,234.5", 0, false},
+		{ini.PeriodDecimal, "1,234.5", 1234.5, true},
+		{ini.PeriodDecimal, "-1,234,567", -1234567, true},
+		{ini.PeriodDecimal, "12,34.5", 0, false},
+		{ini.PeriodDecimal, "1234,567", 0, false},
+		{ini.CommaDecimal, "1.234,56", 1234.56, true},
+		{ini.CommaDecimal, "0,5", 0.5, true},
+		{ini.CommaDecimal, ",5", 0.5, true},
+		{ini.CommaDecimal, "1..234", 0, false},
+		{ini.CommaDecimal, "1.234.5", 0, false},
+		{ini.CommaDecimal, "1,2,3", 0, false},
+		{ini.SpaceGrouped, "1 234,5", 1234.5, true},
+		{ini.SpaceGrouped, "1 234 567", 1234567, true},
+		{ini.SpaceGrouped, "1 234.5", 0, false},
+	}
+	for _, test := range tests {
+		got, err := test.f.ParseFloat(test.input)
+		if ok := err == nil; ok != test.ok || got != test.want {
+			t.Errorf("%+v.ParseFloat(%q): got %v, %v; want %v, ok=%v",
+				test.f, test.input, got, err, test.want, test.ok)
+		}
+	}
+
+	if n, err := ini.CommaDecimal.ParseInt("-12.345"); err != nil || n != -12345 {
+		t.Errorf("ParseInt: got %v, %v; want -12345", n, err)
+	}
+	if n, err := ini.CommaDecimal.ParseInt("1,5"); err == nil {
+		t.Errorf("ParseInt: got %v, want error", n)
+	}
+	if n, err := (ini.NumberFormat{}).ParseInt("010"); err != nil || n != 10 {
+		t.Errorf("ParseInt: got %v, %v; want 10", n, err)
+	}
+}
+
+func TestNumberFormatFor(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   ini.NumberFormat
+		ok     bool
+	}{
+		{"en", ini.PeriodDecimal, true},
+		{"en-US", ini.PeriodDecimal, true},
+		{"de_DE.UTF-8", ini.CommaDecimal, true},
+		{"FR", ini.SpaceGrouped, true},
+		{"xx", ini.NumberFormat{}, false},
+	}
+	for _, test := range tests {
+		got, ok := ini.NumberFormatFor(test.locale)
+		if got != test.want || ok != test.ok {
+			t.Errorf("NumberFormatFor(%q): got %+v, %v; want %+v, %v",
+				test.locale, got, ok, test.want, test.ok)
+		}
+	}
+}
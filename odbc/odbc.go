@@ -0,0 +1,178 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package odbc reads the odbc.ini and odbcinst.ini files used to configure
+// ODBC data sources and drivers.
+//
+// Each data source or driver is described by a section whose keys are its
+// attributes. Section and attribute names are not case sensitive. The
+// sections "ODBC Data Sources" and "ODBC Drivers" list the names of the data
+// sources and drivers, and the section "ODBC" holds global options; these
+// are not reported as data sources or drivers.
+package odbc
+
+import (
+	"io"
+	"strings"
+
+	"github.com/creachadair/ini"
+)
+
+// Dialect is the INI dialect of ODBC configuration files. Comments begin with
+// "#" or ";", and attribute names are folded to lower case.
+var Dialect = ini.Dialect{HashComments: true, FoldKeys: true}
+
+// Names of sections with special meaning.
+const (
+	GlobalSection      = "ODBC"
+	DataSourcesSection = "ODBC Data Sources"
+	DriversSection     = "ODBC Drivers"
+)
+
+// Attrs maps attribute names, in lower case, to their values.
+type Attrs map[string]string
+
+// Get returns the value of the named attribute, ignoring case, or "" if the
+// attribute is not present.
+func (a Attrs) Get(name string) string { return a[strings.ToLower(name)] }
+
+// A Section is a section of an ODBC configuration file.
+type Section struct {
+	Name  string // the name of the section, as written
+	Attrs Attrs  // the attributes defined in the section
+}
+
+// A File is the parsed contents of an ODBC configuration file.
+type File struct {
+	Sections []*Section // in order of first occurrence
+}
+
+// Read parses an ODBC configuration file from r. Keys before the first
+// section header are not permitted. A section that occurs more than once
+// is merged into its first occurrence. If a key is repeated within a
+// section, the last value is used. The values of a key spanning multiple
+// lines are joined with spaces.
+func Read(r io.Reader) (*File, error) {
+	f := new(File)
+	var cur *Section
+	err := ini.Parse(r, ini.Handler{
+		Dialect: Dialect,
+		Section: func(loc ini.Location, name string) error {
+			if cur = f.Section(name); cur == nil {
+				cur = &Section{Name: name, Attrs: make(Attrs)}
+				f.Sections = append(f.Sections, cur)
+			}
+			return nil
+		},
+		KeyValue: func(loc ini.Location, key string, values []string) error {
+			if cur == nil {
+				return &ini.SyntaxError{Location: loc, Desc: "key outside any section", Key: key}
+			}
+			cur.Attrs[key] = ini.JoinValues(values, ini.JoinSpace)
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Section returns the section with the given name, ignoring case, or nil if
+// there is no such section.
+func (f *File) Section(name string) *Section {
+	for _, s := range f.Sections {
+		if strings.EqualFold(s.Name, name) {
+			return s
+		}
+	}
+	return nil
+}
+
+// A DataSource describes a data source (DSN) defined in an odbc.ini file.
+type DataSource struct {
+	Name        string
+	Driver      string // the name or path of the driver
+	Description string
+	Server      string // from "Server" or "Servername"
+	Port        string
+	Database    string
+	Attrs       Attrs // all the attributes of the data source
+}
+
+// DataSources returns the data sources defined in f, in order of occurrence.
+// If a data source does not specify its driver, the driver named for it in
+// the "ODBC Data Sources" section is used.
+func (f *File) DataSources() []DataSource {
+	var list Attrs
+	if s := f.Section(DataSourcesSection); s != nil {
+		list = s.Attrs
+	}
+	var out []DataSource
+	for _, s := range f.Sections {
+		if isSpecial(s.Name) {
+			continue
+		}
+		ds := DataSource{
+			Name:        s.Name,
+			Driver:      s.Attrs.Get("Driver"),
+			Description: s.Attrs.Get("Description"),
+			Server:      s.Attrs.Get("Server"),
+			Port:        s.Attrs.Get("Port"),
+			Database:    s.Attrs.Get("Database"),
+			Attrs:       s.Attrs,
+		}
+		if ds.Driver == "" {
+			ds.Driver = list.Get(s.Name)
+		}
+		if ds.Server == "" {
+			ds.Server = s.Attrs.Get("Servername")
+		}
+		out = append(out, ds)
+	}
+	return out
+}
+
+// A Driver describes a driver defined in an odbcinst.ini file.
+type Driver struct {
+	Name        string
+	Description string
+	Path        string // the path of the driver library
+	Setup       string // the path of the setup library
+	Attrs       Attrs  // all the attributes of the driver
+}
+
+// Drivers returns the drivers defined in f, in order of occurrence.
+func (f *File) Drivers() []Driver {
+	var out []Driver
+	for _, s := range f.Sections {
+		if isSpecial(s.Name) {
+			continue
+		}
+		out = append(out, Driver{
+			Name:        s.Name,
+			Description: s.Attrs.Get("Description"),
+			Path:        s.Attrs.Get("Driver"),
+			Setup:       s.Attrs.Get("Setup"),
+			Attrs:       s.Attrs,
+		})
+	}
+	return out
+}
+
+func isSpecial(name string) bool {
+	return strings.EqualFold(name, GlobalSection) ||
+		strings.EqualFold(name, DataSourcesSection) ||
+		strings.EqualFold(name, DriversSection)
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package odbc_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini/odbc"
+	"github.com/google/go-cmp/cmp"
+)
+
+const odbcINI = `[ODBC Data Sources]
+Sales = PostgreSQL Unicode
+
+[ODBC]
+Trace = No
+
+[sales]
+Description = Sales database
+SERVERNAME  = db.example.com
+Port = 5432
+Database = sales
+
+# The driver is given explicitly.
+[Local]
+Driver = /usr/lib/libsqlite3odbc.so
+Database = /var/lib/local.db
+`
+
+const odbcinstINI = `[ODBC Drivers]
+PostgreSQL Unicode = Installed
+
+[PostgreSQL Unicode]
+Description = PostgreSQL ODBC driver (Unicode)
+Driver = /usr/lib/psqlodbcw.so
+Setup = /usr/lib/libodbcpsqlS.so
+FileUsage = 1
+`
+
+func TestDataSources(t *testing.T) {
+	f, err := odbc.Read(strings.NewReader(odbcINI))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	got := f.DataSources()
+	want := []odbc.DataSource{{
+		Name:        "sales",
+		Driver:      "PostgreSQL Unicode",
+		Description: "Sales database",
+		Server:      "db.example.com",
+		Port:        "5432",
+		Database:    "sales",
+		Attrs: odbc.Attrs{
+			"description": "Sales database",
+			"servername":  "db.example.com",
+			"port":        "5432",
+			"database":    "sales",
+		},
+	}, {
+		Name:     "Local",
+		Driver:   "/usr/lib/libsqlite3odbc.so",
+		Database: "/var/lib/local.db",
+		Attrs: odbc.Attrs{
+			"driver":   "/usr/lib/libsqlite3odbc.so",
+			"database": "/var/lib/local.db",
+		},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("DataSources (-want, +got)\n%s", diff)
+	}
+
+	if s := f.Section("odbc"); s == nil || s.Attrs.Get("TRACE") != "No" {
+		t.Errorf("Section(odbc): got %+v, want trace = No", s)
+	}
+}
+
+func TestDrivers(t *testing.T) {
+	f, err := odbc.Read(strings.NewReader(odbcinstINI))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	got := f.Drivers()
+	want := []odbc.Driver{{
+		Name:        "PostgreSQL Unicode",
+		Description: "PostgreSQL ODBC driver (Unicode)",
+		Path:        "/usr/lib/psqlodbcw.so",
+		Setup:       "/usr/lib/libodbcpsqlS.so",
+		Attrs: odbc.Attrs{
+			"description": "PostgreSQL ODBC driver (Unicode)",
+			"driver":      "/usr/lib/psqlodbcw.so",
+			"setup":       "/usr/lib/libodbcpsqlS.so",
+			"fileusage":   "1",
+		},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Drivers (-want, +got)\n%s", diff)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, input := range []string{"key = outside\n", "[unclosed\n"} {
+		if f, err := odbc.Read(strings.NewReader(input)); err == nil {
+			t.Errorf("Read(%q): got %+v, want error", input, f)
+		}
+	}
+}
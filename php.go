@@ -0,0 +1,96 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import "strings"
+
+// PHP is a Dialect that interprets values as PHP's parse_ini_file does in its
+// normal scanner mode (INI_SCANNER_NORMAL):
+//
+//   - A value enclosed in double or single quotes is delivered without the
+//     quotes. Within double quotes, a backslash escapes a quote or another
+//     backslash. Any text after the closing quote is ignored.
+//   - An unquoted value ends at the first ";", which begins a comment.
+//   - The unquoted values "true", "on", and "yes" are delivered as "1", and
+//     "false", "off", "no", "none", and "null" are delivered as "", ignoring
+//     case.
+//
+// To replace constant names in values, as PHP does for its predefined
+// constants, make a copy of PHP and set its Constants field. To mimic PHP
+// without processing sections, ignore the Section callback.
+//
+// Unlike parse_ini_file, the parser does not build arrays. A key in PHP's
+// array syntax, such as "key[]" or "key[name]", is delivered with its name
+// unchanged, and consecutive occurrences of it are delivered together, as
+// for any repeated key. Use PHPArrayKey to recognize such keys and assemble
+// the arrays, as PHP does:
+//
+//	ext[] = gd        ; PHP: ext => [0 => "gd", 1 => "intl"]
+//	ext[] = intl
+//	db[host] = local  ; PHP: db => ["host" => "local"]
+var PHP = Dialect{PHPValues: true}
+
+// PHPArrayKey reports whether key is written in PHP's array syntax, "base[]"
+// or "base[index]", and if so returns its base name and index. The index of
+// "base[]" is "", denoting the next position of the array.
+func PHPArrayKey(key string) (base, index string, ok bool) {
+	i := strings.IndexByte(key, '[')
+	if i <= 0 || !strings.HasSuffix(key, "]") {
+		return "", "", false
+	}
+	index = key[i+1 : len(key)-1]
+	if strings.ContainsAny(index, "[]") {
+		return "", "", false
+	}
+	return strings.TrimSpace(key[:i]), index, true
+}
+
+// phpValue returns the value of the raw value text s for PHPValues.
+func (d Dialect) phpValue(s string) string {
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		return phpQuoted(s)
+	}
+	if i := strings.IndexByte(s, ';'); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	switch strings.ToLower(s) {
+	case "true", "on", "yes":
+		return "1"
+	case "false", "off", "no", "none", "null":
+		return ""
+	}
+	if c, ok := d.Constants[s]; ok {
+		return c
+	}
+	return s
+}
+
+// phpQuoted returns the contents of the quoted string at the start of s. If
+// the string is not closed, the rest of s is its contents.
+func phpQuoted(s string) string {
+	q := s[0]
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == q {
+			break
+		} else if c == '\\' && q == '"' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+			i++
+			c = s[i]
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPHP(t *testing.T) {
+	d := ini.PHP
+	d.Constants = map[string]string{"E_ALL": "32767"}
+	got, err := runParserWith(ini.Handler{Dialect: d}, `; PHP settings
+display_errors = On
+log_errors = off ; inline comment
+error_reporting = E_ALL
+path = "C:\php\ext;more" ; quoted
+title = "say \"hi\"" trailing
+raw = 'a\b'
+empty = null
+other = E_NOTICE
+[db]
+enabled = TRUE
+`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "comment", "", nil},
+		{2, "key/value", "display_errors", []string{"1"}},
+		{3, "key/value", "log_errors", []string{""}},
+		{4, "key/value", "error_reporting", []string{"32767"}},
+		{5, "key/value", "path", []string{`C:\php\ext;more`}},
+		{6, "key/value", "title", []string{`say "hi"`}},
+		{7, "key/value", "raw", []string{`a\b`}},
+		{8, "key/value", "empty", []string{""}},
+		{9, "key/value", "other", []string{"E_NOTICE"}},
+		{10, "section", "db", nil},
+		{11, "key/value", "enabled", []string{"1"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+}
+
+func TestPHPArrayKeys(t *testing.T) {
+	got, err := runParserWith(ini.Handler{Dialect: ini.PHP}, `ext[] = gd
+ext[] = intl
+db[host] = local
+db[port] = 3306
+`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []result{
+		{1, "key/value", "ext[]", []string{"gd", "intl"}},
+		{3, "key/value", "db[host]", []string{"local"}},
+		{4, "key/value", "db[port]", []string{"3306"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse results (-want, +got)\n%s", diff)
+	}
+
+	tests := []struct {
+		key, base, index string
+		ok               bool
+	}{
+		{"ext[]", "ext", "", true},
+		{"db[host]", "db", "host", true},
+		{"a b[x y]", "a b", "x y", true},
+		{"plain", "", "", false},
+		{"[x]", "", "", false},
+		{"a[b]c", "", "", false},
+		{"a[b][c]", "", "", false},
+	}
+	for _, test := range tests {
+		base, index, ok := ini.PHPArrayKey(test.key)
+		if base != test.base || index != test.index || ok != test.ok {
+			t.Errorf("PHPArrayKey(%q): got (%q, %q, %v), want (%q, %q, %v)",
+				test.key, base, index, ok, test.base, test.index, test.ok)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"fmt"
+	"path"
+)
+
+// A Router dispatches the contents of each section to a Handler chosen by
+// the name of the section, so that the sections of a file can be handled by
+// separate parts of a program. Register a Handler for each section or group
+// of sections with the Section method, and pass the result of the Handler
+// method to Parse.
+//
+// Only the callbacks of the registered handlers are used. Options such as the
+// Dialect should be set on the Handler returned by the Handler method.
+type Router struct {
+	// Fallback receives the contents of sections that match no route.
+	Fallback Handler
+
+	routes []route
+}
+
+type route struct {
+	pattern string
+	h       Handler
+}
+
+// Section registers h to receive the contents of the sections whose names
+// match pattern, using the syntax of path.Match. The pattern "" matches the
+// keys that occur before any section header. If more than one pattern
+// matches a section, the first one registered is used. Section panics if
+// pattern is malformed.
+func (r *Router) Section(pattern string, h Handler) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		panic(fmt.Sprintf("ini: invalid section pattern %q: %v", pattern, err))
+	}
+	r.routes = append(r.routes, route{pattern: pattern, h: h})
+}
+
+// lookup returns the handler for the named section.
+func (r *Router) lookup(name string) Handler {
+	for _, rt := range r.routes {
+		if ok, _ := path.Match(rt.pattern, name); ok {
+			return rt.h
+		}
+	}
+	return r.Fallback
+}
+
+// Handler returns a Handler that delivers each section header, and the
+// comments and keys within the section, to the handler registered for the
+// section. The routes of r should not be changed while the handler is in use.
+func (r *Router) Handler() Handler {
+	return Handler{
+		Comment: func(loc Location, text string) error {
+			if f := r.lookup(loc.Section).Comment; f != nil {
+				return f(loc, text)
+			}
+			return nil
+		},
+		Section: func(loc Location, name string) error {
+			if f := r.lookup(name).Section; f != nil {
+				return f(loc, name)
+			}
+			return nil
+		},
+		KeyValue: func(loc Location, key string, values []string) error {
+			if f := r.lookup(loc.Section).KeyValue; f != nil {
+				return f(loc, key, values)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRouter(t *testing.T) {
+	const input = `top = 1
+[database]
+; primary
+host = db1
+[cache redis]
+size = 10
+[cache memcached]
+size = 20
+[logging]
+level = debug
+`
+	var db, cache, other, top []ini.Entry
+	var r ini.Router
+	r.Section("database", ini.CollectOrdered(&db))
+	r.Section("cache *", ini.CollectOrdered(&cache))
+	r.Section("cache memcached", ini.CollectOrdered(&other)) // shadowed
+	r.Section("", ini.CollectOrdered(&top))
+	r.Fallback = ini.CollectOrdered(&other)
+
+	var comments []string
+	dbh := ini.CollectOrdered(&db)
+	dbh.Comment = func(_ ini.Location, text string) error {
+		comments = append(comments, text)
+		return nil
+	}
+	r.Section("database", dbh) // shadowed by the first route
+
+	h := r.Handler()
+	h.AttachComments = true
+	if err := ini.Parse(strings.NewReader(input), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	keys := func(es []ini.Entry) []string {
+		var out []string
+		for _, e := range es {
+			out = append(out, e.Section+"/"+e.Key+"="+e.Values[0])
+		}
+		return out
+	}
+	check := func(name string, got []ini.Entry, want ...string) {
+		t.Helper()
+		if diff := cmp.Diff(want, keys(got)); diff != "" {
+			t.Errorf("%s (-want, +got)\n%s", name, diff)
+		}
+	}
+	check("top", top, "/top=1")
+	check("database", db, "database/host=db1")
+	check("cache", cache, "cache redis/size=10", "cache memcached/size=20")
+	check("fallback", other, "logging/level=debug")
+	if len(comments) != 0 {
+		t.Errorf("Shadowed route got comments: %q", comments)
+	}
+	if diff := cmp.Diff([]string{"; primary"}, db[0].Comments); diff != "" {
+		t.Errorf("Comments (-want, +got)\n%s", diff)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Section with a bad pattern did not panic")
+		}
+	}()
+	r.Section("[", ini.Handler{})
+}
@@ -0,0 +1,833 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// A Schema describes the sections and keys permitted in an INI file.
+type Schema struct {
+	Sections []*SectionSchema
+
+	// Numbers gives the format of int and float values. The zero value reads
+	// decimal integers and the floats of strconv.ParseFloat.
+	Numbers NumberFormat
+}
+
+// A SectionSchema describes the keys permitted in one section. The section
+// whose Name is "" describes keys that occur before any section header.
+type SectionSchema struct {
+	Name string
+	Doc  string // human-readable documentation, if any
+	Keys []*KeySchema
+
+	// Checks are constraints involving more than one key of the section. Each
+	// check is called after the input has been read, with the keys present in
+	// the section, and reports an error if the constraint is not satisfied.
+	// Use ConstraintError to report the locations of the keys involved.
+	Checks []func(keys map[string]Entry) error
+}
+
+// A KeySchema describes a single key.
+type KeySchema struct {
+	Name     string
+	Type     Type
+	Required bool   // the key must be present
+	Default  string // the default value, if any
+	Doc      string // human-readable documentation, if any
+	Unit     Unit   // the unit of measure, if any
+
+	// Constraints on values. For a list key, each value must satisfy them.
+	Min, Max       *float64 // bounds on numeric values, in canonical units
+	MinLen, MaxLen int      // bounds on length in runes, if positive
+	Enum           []string // if non-empty, the permitted values
+	Pattern        string   // if non-empty, a regexp each value must match
+
+	re *regexp.Regexp // Pattern compiled by SchemaFor, if any
+}
+
+// Type enumerates the types of values a key may have.
+type Type int
+
+// Constants defining the value types for a KeySchema.
+const (
+	TypeString Type = iota // any single value
+	TypeBool               // a single Boolean value, see ParseBool
+	TypeInt                // a single integer value
+	TypeFloat              // a single floating-point value
+	TypeList               // any number of values
+)
+
+var typeNames = [...]string{"string", "bool", "int", "float", "list"}
+
+func (t Type) String() string {
+	if t >= 0 && int(t) < len(typeNames) {
+		return typeNames[t]
+	}
+	return fmt.Sprintf("Type(%d)", int(t))
+}
+
+// Section returns the schema for the named section, or nil if s does not
+// have a section with that name.
+func (s *Schema) Section(name string) *SectionSchema {
+	for _, sec := range s.Sections {
+		if sec.Name == name {
+			return sec
+		}
+	}
+	return nil
+}
+
+// Key returns the schema for the named key, or nil if s does not have a key
+// with that name.
+func (s *SectionSchema) Key(name string) *KeySchema {
+	for _, key := range s.Keys {
+		if key.Name == name {
+			return key
+		}
+	}
+	return nil
+}
+
+// SectionsStartingWith returns the names of the sections of s that begin
+// with prefix, in the order they are defined. It is intended for completion.
+func (s *Schema) SectionsStartingWith(prefix string) []string {
+	var out []string
+	for _, sec := range s.Sections {
+		if sec.Name != "" && strings.HasPrefix(sec.Name, prefix) {
+			out = append(out, sec.Name)
+		}
+	}
+	return out
+}
+
+// KeysFor returns the names of the keys of the named section that begin with
+// prefix, in the order they are defined. It returns nil if s does not have a
+// section with that name. It is intended for completion.
+func (s *Schema) KeysFor(section, prefix string) []string {
+	sec := s.Section(section)
+	if sec == nil {
+		return nil
+	}
+	var out []string
+	for _, key := range sec.Keys {
+		if strings.HasPrefix(key.Name, prefix) {
+			out = append(out, key.Name)
+		}
+	}
+	return out
+}
+
+// ValuesFor returns the possible values of the specified key, if they can be
+// enumerated, beginning with its default value if it has one. It returns nil
+// if the key does not exist or its values cannot be enumerated. It is
+// intended for completion.
+func (s *Schema) ValuesFor(section, key string) []string {
+	sec := s.Section(section)
+	if sec == nil {
+		return nil
+	}
+	ks := sec.Key(key)
+	if ks == nil {
+		return nil
+	} else if len(ks.Enum) != 0 {
+		out := make([]string, 0, len(ks.Enum))
+		if ks.Default != "" {
+			out = append(out, ks.Default)
+		}
+		for _, v := range ks.Enum {
+			if v != ks.Default {
+				out = append(out, v)
+			}
+		}
+		return out
+	} else if ks.Type != TypeBool {
+		return nil
+	}
+	if b, err := ParseBool(ks.Default); err == nil && !b {
+		return []string{"false", "true"}
+	}
+	return []string{"true", "false"}
+}
+
+// SchemaFor derives a schema from the type of v, which must be a struct or a
+// pointer to a struct. SchemaFor panics if v does not have a suitable type.
+//
+// Each exported field of struct type (or pointer to struct) describes a
+// section, whose keys are given by the fields of that struct. Other exported
+// fields describe keys that occur before any section header. A field of type
+// string, bool, []string, or any integer or floating-point type may describe
+// a key. The name of a section or key is the name of its field, unless it is
+// overridden by a field tag:
+//
+//	Port int `ini:"port"`
+//
+// A tag of "-" omits the field. A tag name may be followed by options
+// separated by commas. The "required" option marks a key as required, or
+// marks all the keys of a section as required. The "inidefault" and "inidoc"
+// tags give the default value and documentation for a key or section:
+//
+//	Port int `ini:"port,required" inidefault:"8080" inidoc:"Listen port"`
+//
+// The "iniunit" tag gives the unit of measure for a numeric key, by the name
+// of a Unit such as "seconds":
+//
+//	Timeout float64 `ini:"timeout" iniunit:"seconds"`
+//
+// The "inimin" and "inimax" tags bound numeric values, "iniminlen" and
+// "inimaxlen" bound the length of values, "inienum" lists the permitted
+// values separated by "|", and "inipattern" gives a regular expression that
+// must match the whole of each value:
+//
+//	Level string `ini:"level" inienum:"debug|info|error"`
+//	Port  int    `ini:"port" inimin:"1" inimax:"65535"`
+func SchemaFor(v interface{}) *Schema {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("ini: SchemaFor requires a struct, not %T", v))
+	}
+
+	global := &SectionSchema{Name: ""}
+	s := &Schema{Sections: []*SectionSchema{global}}
+	for _, f := range schemaFields(t) {
+		ft := f.field.Type
+		if ft.Kind() == reflect.Pointer && ft.Elem().Kind() == reflect.Struct {
+			ft = ft.Elem()
+		}
+		if ft.Kind() != reflect.Struct {
+			global.Keys = append(global.Keys, keySchemaFor(f))
+			continue
+		}
+		sec := &SectionSchema{Name: f.name, Doc: f.field.Tag.Get("inidoc")}
+		for _, kf := range schemaFields(ft) {
+			key := keySchemaFor(kf)
+			key.Required = key.Required || f.required
+			sec.Keys = append(sec.Keys, key)
+		}
+		s.Sections = append(s.Sections, sec)
+	}
+	if len(global.Keys) == 0 {
+		s.Sections = s.Sections[1:]
+	}
+	return s
+}
+
+// A schemaField is an exported struct field and its parsed tag.
+type schemaField struct {
+	field    reflect.StructField
+	name     string
+	required bool
+}
+
+func schemaFields(t reflect.Type) []schemaField {
+	var out []schemaField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("ini")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		sf := schemaField{field: f, name: name}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "required":
+				sf.required = true
+			case "":
+			default:
+				panic(fmt.Sprintf("ini: field %s has unknown tag option %q", f.Name, opt))
+			}
+		}
+		out = append(out, sf)
+	}
+	return out
+}
+
+func keySchemaFor(f schemaField) *KeySchema {
+	typ, ok := typeOf(f.field.Type)
+	if !ok {
+		panic(fmt.Sprintf("ini: field %s has unsupported type %v", f.field.Name, f.field.Type))
+	}
+	unit, ok := ParseUnit(f.field.Tag.Get("iniunit"))
+	if !ok || (unit != UnitNone && typ != TypeInt && typ != TypeFloat) {
+		panic(fmt.Sprintf("ini: field %s has invalid unit %q", f.field.Name, f.field.Tag.Get("iniunit")))
+	}
+	key := &KeySchema{
+		Name:     f.name,
+		Type:     typ,
+		Required: f.required,
+		Default:  f.field.Tag.Get("inidefault"),
+		Doc:      f.field.Tag.Get("inidoc"),
+		Unit:     unit,
+		Pattern:  f.field.Tag.Get("inipattern"),
+	}
+	tagErr := func(tag string) {
+		panic(fmt.Sprintf("ini: field %s has invalid %s tag %q", f.field.Name, tag, f.field.Tag.Get(tag)))
+	}
+	for _, b := range []struct {
+		tag string
+		ptr **float64
+	}{{"inimin", &key.Min}, {"inimax", &key.Max}} {
+		if s, ok := f.field.Tag.Lookup(b.tag); ok {
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				tagErr(b.tag)
+			}
+			*b.ptr = &v
+		}
+	}
+	for _, b := range []struct {
+		tag string
+		ptr *int
+	}{{"iniminlen", &key.MinLen}, {"inimaxlen", &key.MaxLen}} {
+		if s, ok := f.field.Tag.Lookup(b.tag); ok {
+			v, err := strconv.Atoi(s)
+			if err != nil || v < 0 {
+				tagErr(b.tag)
+			}
+			*b.ptr = v
+		}
+	}
+	if s, ok := f.field.Tag.Lookup("inienum"); ok {
+		key.Enum = strings.Split(s, "|")
+	}
+	if key.Pattern != "" {
+		re, err := key.compilePattern()
+		if err != nil {
+			tagErr("inipattern")
+		}
+		key.re = re
+	}
+	return key
+}
+
+func typeOf(t reflect.Type) (Type, bool) {
+	switch t.Kind() {
+	case reflect.String:
+		return TypeString, true
+	case reflect.Bool:
+		return TypeBool, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TypeInt, true
+	case reflect.Float32, reflect.Float64:
+		return TypeFloat, true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String {
+			return TypeList, true
+		}
+	}
+	return 0, false
+}
+
+// WriteTemplate writes an example INI file to w describing every section and
+// key of s. Each key is preceded by comments giving its documentation and
+// type. Required keys are written with their default values, if any, while
+// optional keys are written as comments, so that the resulting file is a
+// starting point for a valid configuration.
+func (s *Schema) WriteTemplate(w io.Writer) error {
+	var buf strings.Builder
+	for i, sec := range s.Sections {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		writeDoc(&buf, sec.Doc)
+		if sec.Name != "" {
+			fmt.Fprintf(&buf, "[%s]\n", sec.Name)
+		}
+		for j, key := range sec.Keys {
+			if j > 0 {
+				buf.WriteString("\n")
+			}
+			writeDoc(&buf, key.Doc)
+			fmt.Fprintf(&buf, "; Type: %v", key.Type)
+			if key.Unit != UnitNone {
+				fmt.Fprintf(&buf, " in %v", key.Unit)
+			}
+			if key.Required {
+				buf.WriteString(" (required)")
+			}
+			buf.WriteString("\n")
+			if !key.Required {
+				buf.WriteString("; ")
+			}
+			if key.Default == "" {
+				fmt.Fprintf(&buf, "%s =\n", key.Name)
+			} else {
+				fmt.Fprintf(&buf, "%s = %s\n", key.Name, key.Default)
+			}
+		}
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+// JSONSchema returns a JSON Schema document describing s. The schema treats an
+// INI file as a JSON object whose properties are the keys before any section
+// header, and the sections, each of which is an object whose properties are
+// its keys. Bool, int, and float keys have JSON boolean, integer, and number
+// values respectively, list keys are arrays of strings, and other keys are
+// strings. A numeric key with a Unit is a string, since its value may have a
+// suffix such as "30s" or "10MB"; its pattern admits the forms accepted by
+// Unit.Canonical, but its bounds apply to the canonical value and are not
+// exported.
+func (s *Schema) JSONSchema() ([]byte, error) {
+	root := newJSONObject("")
+	root.Schema = "https://json-schema.org/draft/2020-12/schema"
+	for _, sec := range s.Sections {
+		obj := root
+		if sec.Name != "" {
+			obj = newJSONObject(sec.Doc)
+			root.Properties[sec.Name] = obj
+		}
+		for _, key := range sec.Keys {
+			obj.Properties[key.Name] = key.jsonSchema()
+			if key.Required {
+				obj.Required = append(obj.Required, key.Name)
+			}
+		}
+		if obj != root && len(obj.Required) != 0 {
+			root.Required = append(root.Required, sec.Name)
+		}
+	}
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// jsonSchema is the subset of JSON Schema used by Schema.JSONSchema.
+type jsonSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Type        string                 `json:"type"`
+	Description string                 `json:"description,omitempty"`
+	Properties  map[string]*jsonSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Additional  *bool                  `json:"additionalProperties,omitempty"`
+	Items       *jsonSchema            `json:"items,omitempty"`
+	Default     interface{}            `json:"default,omitempty"`
+	Minimum     *float64               `json:"minimum,omitempty"`
+	Maximum     *float64               `json:"maximum,omitempty"`
+	MinLength   int                    `json:"minLength,omitempty"`
+	MaxLength   int                    `json:"maxLength,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	Pattern     string                 `json:"pattern,omitempty"`
+}
+
+func newJSONObject(doc string) *jsonSchema {
+	return &jsonSchema{
+		Type:        "object",
+		Description: doc,
+		Properties:  make(map[string]*jsonSchema),
+		Additional:  new(bool),
+	}
+}
+
+func (k *KeySchema) jsonSchema() *jsonSchema {
+	out := &jsonSchema{Type: "string", Description: k.Doc}
+	var def interface{} = k.Default
+	switch k.Type {
+	case TypeBool:
+		out.Type = "boolean"
+		def, _ = ParseBool(k.Default)
+	case TypeInt, TypeFloat:
+		if k.Unit != UnitNone {
+			out.Pattern = unitPatterns[k.Unit]
+		} else if k.Type == TypeInt {
+			out.Type = "integer"
+			def, _ = strconv.ParseInt(k.Default, 0, 64)
+		} else {
+			out.Type = "number"
+			def, _ = strconv.ParseFloat(k.Default, 64)
+		}
+	case TypeList:
+		out.Type = "array"
+		out.Items = &jsonSchema{Type: "string"}
+		def = []string{k.Default}
+	}
+	if k.Default != "" {
+		out.Default = def
+	}
+
+	// Constraints apply to the items of an array.
+	c := out
+	if out.Items != nil {
+		c = out.Items
+	}
+	if k.Unit == UnitNone {
+		c.Minimum, c.Maximum = k.Min, k.Max
+	}
+	c.MinLength, c.MaxLength, c.Enum = k.MinLen, k.MaxLen, k.Enum
+	if k.Pattern != "" {
+		c.Pattern = "^(?:" + k.Pattern + ")$"
+	}
+	return out
+}
+
+// unitPatterns gives JSON Schema patterns for the values accepted by
+// Unit.Canonical for each unit.
+var unitPatterns = map[Unit]string{
+	UnitSeconds: `^(?:` + jsonNumber + `|[-+]?(?:(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+)(?:ns|us|µs|μs|ms|s|m|h))+)$`,
+	UnitBytes:   `^(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+)(?:[eE][-+]?[0-9]+)?\s*(?:[bB]|[kKmMgGtT](?:[iI]?[bB])?)?$`,
+	UnitPercent: `^` + jsonNumber + `\s*%?$`,
+}
+
+// jsonNumber is a JSON Schema pattern for a decimal number.
+const jsonNumber = `[-+]?(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+)(?:[eE][-+]?[0-9]+)?`
+
+func writeDoc(buf *strings.Builder, doc string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		buf.WriteString(strings.TrimSpace("; " + line))
+		buf.WriteString("\n")
+	}
+}
+
+// ParseBool reports the truth value of s. It accepts "true", "yes", "on", and
+// "1" as true, and "false", "no", "off", and "0" as false, ignoring case. It
+// reports an error for any other string.
+func ParseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "on", "1":
+		return true, nil
+	case "false", "no", "off", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid Boolean value %q", s)
+}
+
+// ValidationError is the concrete type of error values denoting input that
+// does not conform to a Schema.
+type ValidationError struct {
+	Location        // where the error occurred
+	Desc     string // general description of the error
+	Key      string // if applicable, the key or name affected
+
+	Related []Location // other locations involved, if any
+}
+
+// ConstraintError returns a *ValidationError for a constraint described by
+// desc, involving the keys recorded in entries. The error is reported at the
+// location of the first entry, and the locations of the rest are Related.
+func ConstraintError(desc string, entries ...Entry) *ValidationError {
+	verr := &ValidationError{Desc: desc}
+	var keys []string
+	for i, e := range entries {
+		if i == 0 {
+			verr.Location = e.Location
+		} else {
+			verr.Related = append(verr.Related, e.Location)
+		}
+		keys = append(keys, e.Key)
+	}
+	verr.Key = strings.Join(keys, ", ")
+	return verr
+}
+
+// Is reports whether target is ErrValidation, so that errors.Is(err,
+// ErrValidation) reports true for any *ValidationError.
+func (v *ValidationError) Is(target error) bool { return target == ErrValidation }
+
+func (v *ValidationError) Error() string {
+	var msg string
+	if v.Location.Line > 0 {
+		msg = fmt.Sprintf("line %d: ", v.Location.Line)
+	}
+	if v.Location.File != "" {
+		msg = v.Location.File + ": " + msg
+	}
+	if v.Location.Section != "" {
+		msg += fmt.Sprintf("[%s] ", v.Location.Section)
+	}
+	msg += v.Desc
+	if v.Key != "" {
+		msg += ": " + v.Key
+	}
+	return msg
+}
+
+const (
+	msgUnknownSection = "unknown section"
+	msgUnknownKey     = "unknown key"
+	msgMissingKey     = "missing required key"
+	msgMultipleValues = "multiple values for key"
+)
+
+// Validate parses the INI data from r and checks it against s. Sections and
+// keys not described by s are not permitted, required keys must be present,
+// each value must be valid for its key, and the keys of each section must
+// satisfy its checks. Validate reports the first problem found. Problems
+// with the input have concrete type *ValidationError, or *SyntaxError if r
+// is not valid INI data.
+func (s *Schema) Validate(r io.Reader) error {
+	return s.validate(r, func(err error) error { return err })
+}
+
+// ValidateAll behaves as Validate, but reports all the problems found rather
+// than only the first. If max > 0, at most max problems are reported. If
+// there are any problems, the error has concrete type *ErrorList, sorted by
+// location. The keys of an unknown section are not reported separately. A
+// syntax error ends validation, and is the last error in the list.
+func (s *Schema) ValidateAll(r io.Reader, max int) error {
+	errs := &ErrorList{Max: max}
+	err := s.validate(r, func(err error) error {
+		if errs.Add(err); errs.Truncated {
+			return errs
+		}
+		return nil
+	})
+	errs.Sort()
+	if err != nil && err != errs {
+		errs.Add(err)
+	}
+	return errs.Err()
+}
+
+// Unused parses the INI data from r and returns the keys that are not
+// described by s, in order of occurrence. This includes every key in a
+// section that s does not describe. For a struct type, use SchemaFor to
+// obtain its schema. Unused does not check values; use Validate for that.
+func (s *Schema) Unused(r io.Reader) ([]Entry, error) {
+	var out []Entry
+	var cur *SectionSchema
+	err := Parse(r, Handler{
+		Section: func(loc Location, name string) error {
+			cur = s.Section(name)
+			return nil
+		},
+		KeyValue: func(loc Location, key string, values []string) error {
+			if loc.Section == "" {
+				cur = s.Section("")
+			}
+			if cur == nil || cur.Key(key) == nil {
+				out = append(out, Entry{Location: loc, Key: key, Values: values})
+			}
+			return nil
+		},
+	})
+	return out, err
+}
+
+// Conform parses the INI data from r and reports how it fails to conform to
+// the struct type of v, as described by SchemaFor. The data conform if every
+// section and key corresponds to a field of the struct, every value is valid
+// for its field, and every required key is present; in that case Conform
+// returns no diagnostics. Otherwise, each problem is reported as a
+// Diagnostic with the rule RuleSchema, in order of location. If r is not
+// valid INI data, the error is reported last, with the rule RuleSyntax.
+//
+// Conform does not modify v, which may be a nil pointer to a struct type.
+// Like SchemaFor, it panics if v is not a struct or a pointer to a struct.
+func Conform(r io.Reader, v interface{}) []Diagnostic {
+	err := SchemaFor(v).ValidateAll(r, 0)
+	if err == nil {
+		return nil
+	}
+	var out []Diagnostic
+	for _, err := range err.(*ErrorList).Errors {
+		d := Diagnostic{Location: ErrorLocation(err), Rule: RuleSyntax, Message: err.Error()}
+		switch e := err.(type) {
+		case *ValidationError:
+			d.Rule, d.Message = RuleSchema, e.Desc
+			if e.Key != "" {
+				d.Message += ": " + e.Key
+			}
+		case *SyntaxError:
+			d.Message = e.Desc
+			if e.Key != "" {
+				d.Message += ": " + e.Key
+			}
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// validate implements Validate and ValidateAll. Each problem found is passed
+// to report, and validation stops if report returns a non-nil error.
+func (s *Schema) validate(r io.Reader, report func(error) error) error {
+	var cur *SectionSchema
+	var unknown bool                             // whether the current section is unknown
+	present := make(map[string]map[string]Entry) // section → key → entry
+	headers := make(map[string]Location)
+	if err := Parse(r, Handler{
+		Section: func(loc Location, name string) error {
+			cur = s.Section(name)
+			loc.Section = name
+			if unknown = cur == nil; unknown {
+				return report(&ValidationError{Location: loc, Desc: msgUnknownSection, Key: name})
+			}
+			if _, ok := headers[name]; !ok {
+				headers[name] = loc
+			}
+			return nil
+		},
+		KeyValue: func(loc Location, key string, values []string) error {
+			if loc.Section == "" {
+				cur = s.Section("")
+			} else if unknown {
+				return nil // already reported
+			}
+			var ks *KeySchema
+			if cur != nil {
+				ks = cur.Key(key)
+			}
+			if ks == nil {
+				return report(&ValidationError{Location: loc, Desc: msgUnknownKey, Key: key})
+			}
+			keys := present[loc.Section]
+			if keys == nil {
+				keys = make(map[string]Entry)
+				present[loc.Section] = keys
+			}
+			keys[key] = Entry{Location: loc, Key: key, Values: values}
+			if err := ks.check(loc, values, s.Numbers); err != nil {
+				return report(err)
+			}
+			return nil
+		},
+	}); err != nil {
+		return err
+	}
+
+	for _, sec := range s.Sections {
+		loc := headers[sec.Name]
+		loc.Section = sec.Name
+		keys := present[sec.Name]
+		for _, key := range sec.Keys {
+			if _, ok := keys[key.Name]; key.Required && !ok {
+				err := &ValidationError{Location: loc, Desc: msgMissingKey, Key: key.Name}
+				if err := report(err); err != nil {
+					return err
+				}
+			}
+		}
+		if keys == nil {
+			keys = make(map[string]Entry)
+		}
+		for _, check := range sec.Checks {
+			if err := check(keys); err != nil {
+				if _, ok := err.(*ValidationError); !ok {
+					err = &ValidationError{Location: loc, Desc: err.Error()}
+				}
+				if err := report(err); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// typeLabel returns a description of the type of k for diagnostics.
+func (k *KeySchema) typeLabel() string {
+	if k.Unit != UnitNone {
+		return k.Unit.String()
+	}
+	return k.Type.String()
+}
+
+// check reports whether values are valid for k, using nf to parse numbers.
+func (k *KeySchema) check(loc Location, values []string, nf NumberFormat) error {
+	if k.Type != TypeList && len(values) != 1 {
+		return &ValidationError{Location: loc, Desc: msgMultipleValues, Key: k.Name}
+	}
+	for _, v := range values {
+		if desc := k.checkValue(v, nf); desc != "" {
+			return &ValidationError{Location: loc, Desc: desc, Key: k.Name}
+		}
+	}
+	return nil
+}
+
+// checkValue reports whether v is a valid value for k. It returns "" if v is
+// valid; otherwise it returns a description of the problem.
+func (k *KeySchema) checkValue(v string, nf NumberFormat) string {
+	var num float64
+	var err error
+	isNum := true
+	switch {
+	case k.Unit != UnitNone:
+		num, err = k.Unit.Canonical(v)
+	case k.Type == TypeInt:
+		var n int64
+		n, err = nf.ParseInt(v)
+		num = float64(n)
+	case k.Type == TypeFloat:
+		num, err = nf.ParseFloat(v)
+	case k.Type == TypeBool:
+		_, err = ParseBool(v)
+		isNum = false
+	default:
+		isNum = false
+	}
+	if err != nil {
+		return fmt.Sprintf("invalid %v value %q", k.typeLabel(), v)
+	}
+	if isNum && k.Min != nil && num < *k.Min {
+		return fmt.Sprintf("value %q is less than minimum %v", v, *k.Min)
+	} else if isNum && k.Max != nil && num > *k.Max {
+		return fmt.Sprintf("value %q is greater than maximum %v", v, *k.Max)
+	}
+	if n := utf8.RuneCountInString(v); k.MinLen > 0 && n < k.MinLen {
+		return fmt.Sprintf("value %q is shorter than minimum length %d", v, k.MinLen)
+	} else if k.MaxLen > 0 && n > k.MaxLen {
+		return fmt.Sprintf("value %q is longer than maximum length %d", v, k.MaxLen)
+	}
+	if len(k.Enum) != 0 && !containsString(k.Enum, v) {
+		return fmt.Sprintf("value %q is not one of %q", v, k.Enum)
+	}
+	if k.Pattern != "" {
+		re, err := k.compilePattern()
+		if err != nil {
+			return fmt.Sprintf("invalid pattern %q", k.Pattern)
+		} else if !re.MatchString(v) {
+			return fmt.Sprintf("value %q does not match pattern %q", v, k.Pattern)
+		}
+	}
+	return ""
+}
+
+// compilePattern compiles the pattern of k to match whole values. It reuses
+// the pattern compiled by SchemaFor, unless Pattern has since been changed.
+func (k *KeySchema) compilePattern() (*regexp.Regexp, error) {
+	expr := `^(?:` + k.Pattern + `)$`
+	if k.re != nil && k.re.String() == expr {
+		return k.re, nil
+	}
+	return regexp.Compile(expr)
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
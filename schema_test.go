@@ -0,0 +1,537 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+type testConfig struct {
+	Name string `ini:"name" inidoc:"The name of the service"`
+
+	Server struct {
+		Host    string   `ini:"host" inidefault:"localhost"`
+		Port    int      `ini:"port,required" inidefault:"8080" inidoc:"Listen port"`
+		Debug   bool     `ini:"debug"`
+		Ratio   float64  `ini:"ratio"`
+		Aliases []string `ini:"aliases"`
+		Ignored int      `ini:"-"`
+	} `ini:"server" inidoc:"Server settings"`
+
+	TLS *struct {
+		Cert string `ini:"cert"`
+		Key  string `ini:"key"`
+	} `ini:"tls,required"`
+
+	private int
+}
+
+func TestSchemaFor(t *testing.T) {
+	got := ini.SchemaFor(new(testConfig))
+	want := &ini.Schema{Sections: []*ini.SectionSchema{
+		{Name: "", Keys: []*ini.KeySchema{
+			{Name: "name", Type: ini.TypeString, Doc: "The name of the service"},
+		}},
+		{Name: "server", Doc: "Server settings", Keys: []*ini.KeySchema{
+			{Name: "host", Type: ini.TypeString, Default: "localhost"},
+			{Name: "port", Type: ini.TypeInt, Required: true, Default: "8080", Doc: "Listen port"},
+			{Name: "debug", Type: ini.TypeBool},
+			{Name: "ratio", Type: ini.TypeFloat},
+			{Name: "aliases", Type: ini.TypeList},
+		}},
+		{Name: "tls", Keys: []*ini.KeySchema{
+			{Name: "cert", Type: ini.TypeString, Required: true},
+			{Name: "key", Type: ini.TypeString, Required: true},
+		}},
+	}}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(ini.KeySchema{})); diff != "" {
+		t.Errorf("SchemaFor (-want, +got)\n%s", diff)
+	}
+}
+
+func TestSchemaForPanics(t *testing.T) {
+	for _, v := range []interface{}{nil, 5, "x", struct{ C chan int }{}} {
+		func() {
+			defer func() {
+				if x := recover(); x == nil {
+					t.Errorf("SchemaFor(%T) did not panic", v)
+				}
+			}()
+			ini.SchemaFor(v)
+		}()
+	}
+}
+
+func TestWriteTemplate(t *testing.T) {
+	var buf strings.Builder
+	if err := ini.SchemaFor(testConfig{}).WriteTemplate(&buf); err != nil {
+		t.Fatalf("WriteTemplate failed: %v", err)
+	}
+	const want = `; The name of the service
+; Type: string
+; name =
+
+; Server settings
+[server]
+; Type: string
+; host = localhost
+
+; Listen port
+; Type: int (required)
+port = 8080
+
+; Type: bool
+; debug =
+
+; Type: float
+; ratio =
+
+; Type: list
+; aliases =
+
+[tls]
+; Type: string (required)
+cert =
+
+; Type: string (required)
+key =
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteTemplate (-want, +got)\n%s", diff)
+	}
+
+	// The template must be valid INI text.
+	if _, err := runParser(buf.String()); err != nil {
+		t.Errorf("Parsing template failed: %v", err)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	data, err := ini.SchemaFor(testConfig{}).JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+	var got interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	var want interface{}
+	if err := json.Unmarshal([]byte(`{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["server", "tls"],
+  "properties": {
+    "name": {"type": "string", "description": "The name of the service"},
+    "server": {
+      "type": "object",
+      "description": "Server settings",
+      "additionalProperties": false,
+      "required": ["port"],
+      "properties": {
+        "host": {"type": "string", "default": "localhost"},
+        "port": {"type": "integer", "default": 8080, "description": "Listen port"},
+        "debug": {"type": "boolean"},
+        "ratio": {"type": "number"},
+        "aliases": {"type": "array", "items": {"type": "string"}}
+      }
+    },
+    "tls": {
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["cert", "key"],
+      "properties": {
+        "cert": {"type": "string"},
+        "key": {"type": "string"}
+      }
+    }
+  }
+}`), &want); err != nil {
+		t.Fatalf("Invalid test JSON: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("JSONSchema (-want, +got)\n%s", diff)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	s := ini.SchemaFor(testConfig{})
+	check := func(name string, got, want []string) {
+		t.Helper()
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%s (-want, +got)\n%s", name, diff)
+		}
+	}
+	check("Sections", s.SectionsStartingWith(""), []string{"server", "tls"})
+	check("Sections t", s.SectionsStartingWith("t"), []string{"tls"})
+	check("Sections x", s.SectionsStartingWith("x"), nil)
+	check("Keys global", s.KeysFor("", ""), []string{"name"})
+	check("Keys server", s.KeysFor("server", ""), []string{"host", "port", "debug", "ratio", "aliases"})
+	check("Keys server p", s.KeysFor("server", "p"), []string{"port"})
+	check("Keys missing", s.KeysFor("nonesuch", ""), nil)
+	check("Values debug", s.ValuesFor("server", "debug"), []string{"true", "false"})
+	check("Values port", s.ValuesFor("server", "port"), nil)
+	check("Values missing", s.ValuesFor("server", "nonesuch"), nil)
+}
+
+func TestValidateNumbers(t *testing.T) {
+	s := ini.SchemaFor(testConfig{})
+	s.Numbers = ini.CommaDecimal
+	const input = "[server]\nport = 8.080\nratio = 1.234,5\n[tls]\ncert=a\nkey=b\n"
+	if err := s.Validate(strings.NewReader(input)); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+	if err := s.Validate(strings.NewReader("[server]\nport = 8,5\n")); err == nil {
+		t.Error("Validate: got nil, want error")
+	}
+}
+
+func TestSchemaConstraints(t *testing.T) {
+	var cfg struct {
+		Port  int      `ini:"port" inimin:"1" inimax:"65535"`
+		Level string   `ini:"level" inienum:"debug|info|error" inidefault:"info"`
+		Name  string   `ini:"name" iniminlen:"2" inimaxlen:"4"`
+		Tags  []string `ini:"tags" inipattern:"[a-z]+"`
+		Wait  int      `ini:"wait" iniunit:"seconds" inimax:"60"`
+	}
+	s := ini.SchemaFor(&cfg)
+
+	if got, want := s.ValuesFor("", "level"), []string{"info", "debug", "error"}; !cmp.Equal(got, want) {
+		t.Errorf("ValuesFor level: got %q, want %q", got, want)
+	}
+
+	const valid = "port = 80\nlevel = debug\nname = abc\ntags = x\n  yz\nwait = 1m\n"
+	if err := s.Validate(strings.NewReader(valid)); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+
+	tests := []struct {
+		input, desc string
+	}{
+		{"port = 0", `value "0" is less than minimum 1`},
+		{"port = 70000", `value "70000" is greater than maximum 65535`},
+		{"level = trace", `value "trace" is not one of ["debug" "info" "error"]`},
+		{"name = a", `value "a" is shorter than minimum length 2`},
+		{"name = abcde", `value "abcde" is longer than maximum length 4`},
+		{"tags = ok\n  Not", `value "Not" does not match pattern "[a-z]+"`},
+		{"wait = 2m", `value "2m" is greater than maximum 60`},
+	}
+	for _, test := range tests {
+		err := s.Validate(strings.NewReader(test.input))
+		if verr, ok := err.(*ini.ValidationError); !ok || verr.Desc != test.desc || verr.Line != 1 {
+			t.Errorf("Validate(%q): got %v, want line 1: %s", test.input, err, test.desc)
+		} else if !errors.Is(err, ini.ErrValidation) || errors.Is(err, ini.ErrSyntax) {
+			t.Errorf("Validate(%q): error %v has the wrong category", test.input, err)
+		}
+	}
+
+	// A pattern changed after SchemaFor compiled it is used as changed.
+	tags := s.Section("").Key("tags")
+	tags.Pattern = "[0-9]+"
+	if err := s.Validate(strings.NewReader("tags = 12\n")); err != nil {
+		t.Errorf("Validate with changed pattern: %v", err)
+	} else if err := s.Validate(strings.NewReader("tags = x\n")); err == nil {
+		t.Error("Validate with changed pattern: got nil error for x")
+	}
+	tags.Pattern = "[a-z]+"
+
+	data, err := s.JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+	for _, want := range []string{`"maximum": 65535`, `"enum": [`, `"pattern": "^(?:[a-z]+)$"`, `"maxLength": 4`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("JSONSchema: missing %q in output:\n%s", want, data)
+		}
+	}
+}
+
+func TestSchemaChecks(t *testing.T) {
+	s := ini.SchemaFor(testConfig{})
+	tls := s.Section("tls")
+	tls.Keys[0].Required = false
+	tls.Keys[1].Required = false
+	tls.Checks = append(tls.Checks, func(keys map[string]ini.Entry) error {
+		cert, hasCert := keys["cert"]
+		key, hasKey := keys["key"]
+		if hasCert && !hasKey {
+			return ini.ConstraintError("cert requires key", cert)
+		} else if hasCert && cert.Values[0] == key.Values[0] {
+			return ini.ConstraintError("cert and key must differ", cert, key)
+		}
+		return nil
+	})
+
+	tests := []struct {
+		input   string
+		want    string
+		related []ini.Location
+	}{
+		{"[server]\nport=1\n[tls]\ncert=a\n", "line 4: [tls] cert requires key: cert", nil},
+		{"[server]\nport=1\n[tls]\ncert=a\nkey=a\n", "line 4: [tls] cert and key must differ: cert, key",
+			[]ini.Location{{Line: 5, Section: "tls"}}},
+	}
+	for _, test := range tests {
+		err := s.Validate(strings.NewReader(test.input))
+		verr, ok := err.(*ini.ValidationError)
+		if !ok || verr.Error() != test.want {
+			t.Errorf("Validate(%q): got %v, want %q", test.input, err, test.want)
+		} else if diff := cmp.Diff(test.related, verr.Related); diff != "" {
+			t.Errorf("Validate(%q) related (-want, +got)\n%s", test.input, diff)
+		}
+	}
+
+	s.Section("server").Checks = []func(map[string]ini.Entry) error{
+		func(map[string]ini.Entry) error { return errors.New("always fails") },
+	}
+	err := s.Validate(strings.NewReader("[server]\nport=1\n"))
+	if err == nil || err.Error() != "line 1: [server] always fails" {
+		t.Errorf("Validate: got %v, want always fails", err)
+	}
+
+	// Errors in included files name the file.
+	verr := &ini.ValidationError{Location: ini.Location{Line: 3, Section: "s", File: "inc.ini"}, Desc: "bad", Key: "k"}
+	if got, want := verr.Error(), "inc.ini: line 3: [s] bad: k"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := ini.SchemaFor(testConfig{})
+	const valid = `
+name = test
+[server]
+port = 128
+debug = yes
+ratio = 0.5
+aliases = a
+  b
+[tls]
+cert = x
+key = y
+`
+	if err := s.Validate(strings.NewReader(valid)); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		line  int
+		desc  string
+		key   string
+	}{
+		{"[server]\nport=1\n[tls]\ncert=a\nkey=b\n[other]", 6, "unknown section", "other"},
+		{"[server]\nport=1\nbogus=2", 3, "unknown key", "bogus"},
+		{"size=3\n", 1, "unknown key", "size"},
+		{"[server]\nport=x", 2, `invalid int value "x"`, "port"},
+		{"[server]\nport=1\ndebug=maybe", 3, `invalid bool value "maybe"`, "debug"},
+		{"[server]\nport=1\nratio=1/2", 3, `invalid float value "1/2"`, "ratio"},
+		{"[server]\nport=1\n 2", 2, "multiple values for key", "port"},
+		{"[server]\nhost=h\n[tls]\ncert=a\nkey=b", 1, "missing required key", "port"},
+		{"[server]\nport=1\n", 0, "missing required key", "cert"},
+	}
+	for _, test := range tests {
+		err := s.Validate(strings.NewReader(test.input))
+		verr, ok := err.(*ini.ValidationError)
+		if !ok {
+			t.Errorf("Validate(%q): got %v, want validation error", test.input, err)
+			continue
+		}
+		if verr.Line != test.line || verr.Desc != test.desc || verr.Key != test.key {
+			t.Errorf("Validate(%q): got (%d, %q, %q), want (%d, %q, %q)", test.input,
+				verr.Line, verr.Desc, verr.Key, test.line, test.desc, test.key)
+		}
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	s := ini.SchemaFor(testConfig{})
+	const input = "size = 3\n[other]\nx = 1\n[server]\nport = x\ndebug = maybe\n"
+	type problem struct {
+		Line      int
+		Desc, Key string
+	}
+	tests := []struct {
+		max       int
+		want      []problem
+		truncated bool
+	}{
+		{0, []problem{
+			{0, "missing required key", "cert"},
+			{0, "missing required key", "key"},
+			{1, "unknown key", "size"},
+			{2, "unknown section", "other"},
+			{5, `invalid int value "x"`, "port"},
+			{6, `invalid bool value "maybe"`, "debug"},
+		}, false},
+		{3, []problem{
+			{1, "unknown key", "size"},
+			{2, "unknown section", "other"},
+			{5, `invalid int value "x"`, "port"},
+		}, true},
+	}
+	for _, test := range tests {
+		err := s.ValidateAll(strings.NewReader(input), test.max)
+		errs, ok := err.(*ini.ErrorList)
+		if !ok {
+			t.Fatalf("ValidateAll(%d): got %v, want error list", test.max, err)
+		}
+		var got []problem
+		for _, e := range errs.Errors {
+			verr := e.(*ini.ValidationError)
+			got = append(got, problem{verr.Line, verr.Desc, verr.Key})
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ValidateAll(%d) (-want, +got)\n%s", test.max, diff)
+		}
+		if errs.Truncated != test.truncated {
+			t.Errorf("ValidateAll(%d): truncated is %v, want %v", test.max, errs.Truncated, test.truncated)
+		}
+	}
+
+	if err := s.ValidateAll(strings.NewReader("size = 3\n; end\n[bad"), 0); err == nil {
+		t.Error("ValidateAll: got nil, want error")
+	} else if errs := err.(*ini.ErrorList); len(errs.Errors) != 2 || !errors.Is(err, ini.ErrSyntax) {
+		t.Errorf("ValidateAll: got %v, want a validation and a syntax error", err)
+	}
+}
+
+func TestUnused(t *testing.T) {
+	s := ini.SchemaFor(testConfig{})
+	got, err := s.Unused(strings.NewReader(`name = x
+stale = 1
+[server]
+port = nope
+legacy_mode = on
+[old]
+a = 1
+[tls]
+cert = c
+`))
+	if err != nil {
+		t.Fatalf("Unused failed: %v", err)
+	}
+	want := []ini.Entry{
+		{Location: ini.Location{Line: 2}, Key: "stale", Values: []string{"1"}},
+		{Location: ini.Location{Line: 5, Section: "server"}, Key: "legacy_mode", Values: []string{"on"}},
+		{Location: ini.Location{Line: 7, Section: "old"}, Key: "a", Values: []string{"1"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unused (-want, +got)\n%s", diff)
+	}
+}
+
+func TestConform(t *testing.T) {
+	const good = "name = x\n[server]\nport = 80\n[tls]\ncert = c\nkey = k\n"
+	var cfg *testConfig
+	if ds := ini.Conform(strings.NewReader(good), cfg); len(ds) != 0 {
+		t.Errorf("Conform: got %v, want no diagnostics", ds)
+	}
+
+	got := ini.Conform(strings.NewReader(`name = x
+stale = 1
+[server]
+port = nope
+[tls]
+cert = c
+key = k
+[broken
+`), cfg)
+	type diag struct {
+		Line          int
+		Rule, Message string
+	}
+	var diags []diag
+	for _, d := range got {
+		diags = append(diags, diag{d.Line, d.Rule, d.Message})
+	}
+	want := []diag{
+		{2, ini.RuleSchema, "unknown key: stale"},
+		{4, ini.RuleSchema, `invalid int value "nope": port`},
+		{8, ini.RuleSyntax, "unclosed section header: broken"},
+	}
+	if diff := cmp.Diff(want, diags); diff != "" {
+		t.Errorf("Conform (-want, +got)\n%s", diff)
+	}
+	if cfg != nil {
+		t.Error("Conform modified its argument")
+	}
+}
+
+func TestJSONSchemaUnits(t *testing.T) {
+	var cfg struct {
+		Wait  int     `ini:"wait" iniunit:"seconds" inidefault:"30s"`
+		Size  int64   `ini:"size" iniunit:"bytes"`
+		Share float64 `ini:"share" iniunit:"percent"`
+	}
+	data, err := ini.SchemaFor(&cfg).JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+	var doc struct {
+		Properties map[string]struct {
+			Type    string      `json:"type"`
+			Pattern string      `json:"pattern"`
+			Default interface{} `json:"default"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Decoding schema: %v", err)
+	}
+	if got := doc.Properties["wait"].Default; got != "30s" {
+		t.Errorf("Default for wait: got %v, want 30s", got)
+	}
+	tests := []struct {
+		key         string
+		good, wrong []string
+	}{
+		{"wait", []string{"30", "1.5", "30s", "1m30s", "-2h", "250ms"}, []string{"", "soon", "30 s", "1d"}},
+		{"size", []string{"512", "4KiB", "1.5GB", "10 MB", "2k", "7b"}, []string{"-1", "1XB", "MB", "1 2"}},
+		{"share", []string{"50", "12.5%", "-3 %"}, []string{"%", "half", "5%%"}},
+	}
+	for _, test := range tests {
+		p := doc.Properties[test.key]
+		if p.Type != "string" {
+			t.Errorf("Type of %s: got %q, want string", test.key, p.Type)
+		}
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			t.Fatalf("Pattern of %s: %v", test.key, err)
+		}
+		for _, v := range test.good {
+			if !re.MatchString(v) {
+				t.Errorf("Pattern of %s does not match %q", test.key, v)
+			}
+			input := fmt.Sprintf("%s = %s\n", test.key, v)
+			if err := ini.SchemaFor(&cfg).Validate(strings.NewReader(input)); err != nil {
+				t.Errorf("Validate %q: %v", input, err)
+			}
+		}
+		for _, v := range test.wrong {
+			if re.MatchString(v) {
+				t.Errorf("Pattern of %s matches %q", test.key, v)
+			}
+		}
+	}
+}
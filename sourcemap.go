@@ -0,0 +1,138 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import "sort"
+
+// A SourceMap records where the lines of preprocessed input came from, so
+// that the locations reported by the parser can be translated back to the
+// input as originally written. A preprocessor, such as a template expander,
+// calls Add for each run of lines it copies from its input to its output.
+// The zero value is an empty map ready for use.
+type SourceMap struct {
+	spans []sourceSpan // ordered by file and line
+}
+
+type sourceSpan struct {
+	file string   // the file of the preprocessed output ("" for the main input)
+	line int      // the first line of the span in the output
+	n    int      // the number of lines in the span
+	orig Location // the origin of the first line
+}
+
+// Add records that n lines of preprocessed output, beginning at the line and
+// file given by at, came from consecutive lines of the original input
+// beginning at the line and file given by orig. Where spans overlap, the
+// span that begins nearest before a line is used for it.
+func (m *SourceMap) Add(at Location, n int, orig Location) {
+	if n <= 0 {
+		return
+	}
+	s := sourceSpan{
+		file: at.File, line: at.Line, n: n,
+		orig: Location{Line: orig.Line, File: orig.File},
+	}
+	i := sort.Search(len(m.spans), func(i int) bool {
+		return m.spans[i].after(s.file, s.line)
+	})
+	m.spans = append(m.spans, sourceSpan{})
+	copy(m.spans[i+1:], m.spans[i:])
+	m.spans[i] = s
+}
+
+// after reports whether s begins after the given line of file.
+func (s sourceSpan) after(file string, line int) bool {
+	if s.file != file {
+		return s.file > file
+	}
+	return s.line > line
+}
+
+// Translate returns a copy of loc whose File and Line give the original
+// position of the line at loc. If no span added to m covers loc, Translate
+// returns loc unchanged and false.
+func (m *SourceMap) Translate(loc Location) (Location, bool) {
+	i := sort.Search(len(m.spans), func(i int) bool {
+		return m.spans[i].after(loc.File, loc.Line)
+	})
+	for i--; i >= 0 && m.spans[i].file == loc.File; i-- {
+		if s := m.spans[i]; loc.Line < s.line+s.n {
+			loc.File = s.orig.File
+			loc.Line = s.orig.Line + loc.Line - s.line
+			return loc, true
+		}
+	}
+	return loc, false
+}
+
+// Handler returns a Handler that translates the location of each callback
+// through m before delivering it to h. The options of h are unchanged.
+func (m *SourceMap) Handler(h Handler) Handler {
+	tr := func(loc Location) Location { loc, _ = m.Translate(loc); return loc }
+	if f := h.Comment; f != nil {
+		h.Comment = func(loc Location, text string) error { return f(tr(loc), text) }
+	}
+	if f := h.Section; f != nil {
+		h.Section = func(loc Location, name string) error { return f(tr(loc), name) }
+	}
+	if f := h.KeyValue; f != nil {
+		h.KeyValue = func(loc Location, key string, values []string) error {
+			return f(tr(loc), key, values)
+		}
+	}
+	return h
+}
+
+// TranslateError returns a copy of err with its location translated through
+// m, if err is a *SyntaxError, *ValidationError, *HandlerError,
+// *CallbackError, or *IOError. If err is an *ErrorList, each of its errors
+// is translated. Any other error is returned unchanged.
+func (m *SourceMap) TranslateError(err error) error {
+	tr := func(loc Location) Location { loc, _ = m.Translate(loc); return loc }
+	switch e := err.(type) {
+	case *SyntaxError:
+		c := *e
+		c.Location = tr(c.Location)
+		return &c
+	case *ValidationError:
+		c := *e
+		c.Location = tr(c.Location)
+		c.Related = make([]Location, len(e.Related))
+		for i, loc := range e.Related {
+			c.Related[i] = tr(loc)
+		}
+		return &c
+	case *HandlerError:
+		c := *e
+		c.Location = tr(c.Location)
+		return &c
+	case *CallbackError:
+		c := *e
+		c.Location = tr(c.Location)
+		return &c
+	case *IOError:
+		c := *e
+		c.Location = tr(c.Location)
+		return &c
+	case *ErrorList:
+		c := *e
+		c.Errors = make([]error, len(e.Errors))
+		for i, err := range e.Errors {
+			c.Errors[i] = m.TranslateError(err)
+		}
+		return &c
+	}
+	return err
+}
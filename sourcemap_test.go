@@ -0,0 +1,61 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSourceMap(t *testing.T) {
+	// A preprocessor expanded a template, copying lines 1-2 of base.ini,
+	// then lines 5-6 of extra.ini, then lines 3-4 of base.ini.
+	var m ini.SourceMap
+	m.Add(ini.Location{Line: 1}, 2, ini.Location{File: "base.ini", Line: 1})
+	m.Add(ini.Location{Line: 5}, 2, ini.Location{File: "base.ini", Line: 3})
+	m.Add(ini.Location{Line: 3}, 2, ini.Location{File: "extra.ini", Line: 5})
+	lines := []string{"[a]", "x = 1", "[b]", "y = 2", "; end", "[bad"}
+
+	var got []ini.Entry
+	err := ini.ParseLines(lines, ini.Location{}, m.Handler(ini.CollectOrdered(&got)))
+	want := []ini.Entry{
+		{Location: ini.Location{Line: 2, Section: "a", File: "base.ini"}, Key: "x", Values: []string{"1"}},
+		{Location: ini.Location{Line: 6, Section: "b", File: "extra.ini"}, Key: "y", Values: []string{"2"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Entries (-want, +got)\n%s", diff)
+	}
+
+	terr := m.TranslateError(err)
+	if loc := ini.ErrorLocation(terr); loc.File != "base.ini" || loc.Line != 4 {
+		t.Errorf("TranslateError(%v): got %v, want base.ini line 4", err, terr)
+	}
+	if loc := ini.ErrorLocation(err); loc.Line != 6 {
+		t.Errorf("TranslateError modified the original error: %v", err)
+	}
+	list := &ini.ErrorList{Errors: []error{err}}
+	if loc := ini.ErrorLocation(m.TranslateError(list)); loc.File != "base.ini" {
+		t.Errorf("TranslateError(list): got %+v, want base.ini", loc)
+	}
+
+	if loc, ok := m.Translate(ini.Location{Line: 9}); ok || loc.Line != 9 {
+		t.Errorf("Translate(9): got %+v, %v, want unchanged", loc, ok)
+	}
+	if loc, ok := m.Translate(ini.Location{Line: 1, File: "other"}); ok || loc.File != "other" {
+		t.Errorf("Translate(other): got %+v, %v, want unchanged", loc, ok)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import "strings"
+
+// A Trie is a prefix tree of keys indexed by path, for consumers that look up
+// many keys by path or by longest matching prefix. The path of a key is its
+// section name and key name joined by "/", or just the key name for keys that
+// occur before any section header. Paths are matched a segment at a time, so
+// the path "a/b" is a prefix of "a/b/c" but not of "a/bc".
+//
+// The zero value is an empty trie ready for use. Use CollectTrie to populate
+// a Trie from the parser.
+type Trie struct {
+	root trieNode
+	size int
+}
+
+type trieNode struct {
+	entry *Entry
+	next  map[string]*trieNode
+}
+
+// KeyPath returns the trie path for key in the specified section.
+func KeyPath(section, key string) string {
+	if section == "" {
+		return key
+	}
+	return section + "/" + key
+}
+
+// CollectTrie returns a Handler that adds an Entry to t for each key. If a
+// key occurs more than once in a section, its values are appended to the
+// existing entry, which retains the location of the first occurrence.
+func CollectTrie(t *Trie) Handler {
+	return Handler{
+		KeyValue: func(loc Location, key string, values []string) error {
+			t.add(Entry{Location: loc, Key: key, Values: values})
+			return nil
+		},
+	}
+}
+
+// Len reports the number of entries in t.
+func (t *Trie) Len() int { return t.size }
+
+func (t *Trie) add(e Entry) {
+	n := &t.root
+	for _, seg := range strings.Split(KeyPath(e.Section, e.Key), "/") {
+		next := n.next[seg]
+		if next == nil {
+			if n.next == nil {
+				n.next = make(map[string]*trieNode)
+			}
+			next = new(trieNode)
+			n.next[seg] = next
+		}
+		n = next
+	}
+	if n.entry == nil {
+		n.entry = &e
+		t.size++
+	} else {
+		n.entry.Values = append(n.entry.Values, e.Values...)
+	}
+}
+
+// Lookup reports the entry whose path is exactly path, if any.
+func (t *Trie) Lookup(path string) (Entry, bool) {
+	n := &t.root
+	for _, seg := range strings.Split(path, "/") {
+		if n = n.next[seg]; n == nil {
+			return Entry{}, false
+		}
+	}
+	if n.entry == nil {
+		return Entry{}, false
+	}
+	return *n.entry, true
+}
+
+// LongestPrefix reports the entry whose path is the longest prefix of path,
+// matching whole segments. It reports false if no entry's path is a prefix of
+// path.
+func (t *Trie) LongestPrefix(path string) (Entry, bool) {
+	var best *Entry
+	n := &t.root
+	for _, seg := range strings.Split(path, "/") {
+		if n = n.next[seg]; n == nil {
+			break
+		}
+		if n.entry != nil {
+			best = n.entry
+		}
+	}
+	if best == nil {
+		return Entry{}, false
+	}
+	return *best, true
+}
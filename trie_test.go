@@ -0,0 +1,93 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTrie(t *testing.T) {
+	const input = `default = off
+[flags]
+beta = on
+beta/users = some
+[flags/beta/users]
+42 = all
+[flags]
+beta = more
+`
+	var tr ini.Trie
+	if err := ini.Parse(strings.NewReader(input), ini.CollectTrie(&tr)); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got, want := tr.Len(), 4; got != want {
+		t.Errorf("Len: got %d, want %d", got, want)
+	}
+
+	if got, want := ini.KeyPath("", "x"), "x"; got != want {
+		t.Errorf("KeyPath: got %q, want %q", got, want)
+	}
+	if got, want := ini.KeyPath("a/b", "x"), "a/b/x"; got != want {
+		t.Errorf("KeyPath: got %q, want %q", got, want)
+	}
+
+	lookup := []struct {
+		path string
+		want []string // nil if not found
+	}{
+		{"default", []string{"off"}},
+		{"flags/beta", []string{"on", "more"}},
+		{"flags/beta/users", []string{"some"}},
+		{"flags/beta/users/42", []string{"all"}},
+		{"flags", nil},
+		{"flags/bet", nil},
+		{"flags/beta/users/43", nil},
+	}
+	for _, test := range lookup {
+		e, ok := tr.Lookup(test.path)
+		if ok != (test.want != nil) {
+			t.Errorf("Lookup(%q): got ok=%v, want %v", test.path, ok, !ok)
+		} else if diff := cmp.Diff(test.want, e.Values); diff != "" {
+			t.Errorf("Lookup(%q) values (-want, +got)\n%s", test.path, diff)
+		}
+	}
+
+	prefix := []struct {
+		path string
+		key  string // "" if not found
+		line int
+	}{
+		{"default/x/y", "default", 1},
+		{"flags/beta", "beta", 3},
+		{"flags/beta/other", "beta", 3},
+		{"flags/beta/users/7", "beta/users", 4},
+		{"flags/beta/users/42/x", "42", 6},
+		{"flags/betas", "", 0},
+		{"other", "", 0},
+	}
+	for _, test := range prefix {
+		e, ok := tr.LongestPrefix(test.path)
+		if ok != (test.key != "") {
+			t.Errorf("LongestPrefix(%q): got ok=%v, want %v", test.path, ok, !ok)
+		} else if e.Key != test.key || e.Line != test.line {
+			t.Errorf("LongestPrefix(%q): got %q at line %d, want %q at line %d",
+				test.path, e.Key, e.Line, test.key, test.line)
+		}
+	}
+}
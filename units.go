@@ -0,0 +1,107 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Unit enumerates the units of measure a key may declare.
+type Unit int
+
+// Constants defining the units for a KeySchema.
+const (
+	UnitNone    Unit = iota // a plain value
+	UnitSeconds             // a duration; canonical unit seconds
+	UnitBytes               // a size; canonical unit bytes
+	UnitPercent             // a percentage; canonical unit percent
+)
+
+var unitNames = [...]string{"", "seconds", "bytes", "percent"}
+
+func (u Unit) String() string {
+	if u >= 0 && int(u) < len(unitNames) {
+		return unitNames[u]
+	}
+	return fmt.Sprintf("Unit(%d)", int(u))
+}
+
+// ParseUnit returns the Unit with the given name, as returned by its String
+// method. It reports false if name does not denote a unit.
+func ParseUnit(name string) (Unit, bool) {
+	for i, s := range unitNames {
+		if s == name {
+			return Unit(i), true
+		}
+	}
+	return UnitNone, false
+}
+
+// byteScale maps lower-cased size suffixes to multipliers. Decimal (SI)
+// suffixes are powers of 1000; binary (IEC) suffixes and single letters are
+// powers of 1024.
+var byteScale = map[string]float64{
+	"": 1, "b": 1,
+	"k": 1 << 10, "kib": 1 << 10, "kb": 1e3,
+	"m": 1 << 20, "mib": 1 << 20, "mb": 1e6,
+	"g": 1 << 30, "gib": 1 << 30, "gb": 1e9,
+	"t": 1 << 40, "tib": 1 << 40, "tb": 1e12,
+}
+
+// Canonical parses s as a quantity in unit u and returns its value in the
+// canonical unit for u:
+//
+//   - Seconds accepts a plain number of seconds, or a duration in the format
+//     of time.ParseDuration such as "1m30s".
+//   - Bytes accepts a non-negative number with an optional size suffix, such
+//     as "512", "4KiB", or "1.5GB". The suffixes kB, MB, GB, and TB are powers
+//     of 1000; KiB, MiB, GiB, and TiB, and the abbreviations K, M, G, and T,
+//     are powers of 1024. Suffixes are not case-sensitive.
+//   - Percent accepts a number with an optional trailing "%".
+//   - UnitNone accepts a plain number.
+func (u Unit) Canonical(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	switch u {
+	case UnitSeconds:
+		if v, err := strconv.ParseFloat(s, 64); err == nil {
+			return v, nil
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return d.Seconds(), nil
+
+	case UnitBytes:
+		i := strings.LastIndexAny(s, "0123456789.") + 1
+		scale, ok := byteScale[strings.ToLower(strings.TrimSpace(s[i:]))]
+		v, err := strconv.ParseFloat(s[:i], 64)
+		if !ok || err != nil || v < 0 {
+			return 0, fmt.Errorf("invalid size %q", s)
+		}
+		return v * scale, nil
+
+	case UnitPercent:
+		v, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, "%")), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid percentage %q", s)
+		}
+		return v, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+)
+
+func TestUnitCanonical(t *testing.T) {
+	tests := []struct {
+		unit  ini.Unit
+		input string
+		want  float64
+		ok    bool
+	}{
+		{ini.UnitNone, "2.5", 2.5, true},
+		{ini.UnitNone, "2s", 0, false},
+
+		{ini.UnitSeconds, "90", 90, true},
+		{ini.UnitSeconds, "1m30s", 90, true},
+		{ini.UnitSeconds, "250ms", 0.25, true},
+		{ini.UnitSeconds, "soon", 0, false},
+
+		{ini.UnitBytes, "512", 512, true},
+		{ini.UnitBytes, "4KiB", 4096, true},
+		{ini.UnitBytes, "4 k", 4096, true},
+		{ini.UnitBytes, "1.5GB", 1.5e9, true},
+		{ini.UnitBytes, "2mib", 2 << 20, true},
+		{ini.UnitBytes, "-1", 0, false},
+		{ini.UnitBytes, "3 parsecs", 0, false},
+		{ini.UnitBytes, "KB", 0, false},
+
+		{ini.UnitPercent, "50%", 50, true},
+		{ini.UnitPercent, "12.5", 12.5, true},
+		{ini.UnitPercent, "half", 0, false},
+	}
+	for _, test := range tests {
+		got, err := test.unit.Canonical(test.input)
+		if ok := err == nil; ok != test.ok || got != test.want {
+			t.Errorf("%v.Canonical(%q): got %v, %v; want %v, ok=%v",
+				test.unit, test.input, got, err, test.want, test.ok)
+		}
+	}
+}
+
+func TestSchemaUnits(t *testing.T) {
+	var cfg struct {
+		Limits struct {
+			Timeout int     `ini:"timeout" iniunit:"seconds"`
+			Memory  int64   `ini:"memory" iniunit:"bytes"`
+			Load    float64 `ini:"load" iniunit:"percent"`
+		} `ini:"limits"`
+	}
+	s := ini.SchemaFor(&cfg)
+	if u := s.Section("limits").Key("memory").Unit; u != ini.UnitBytes {
+		t.Errorf("Unit for memory: got %v, want %v", u, ini.UnitBytes)
+	}
+	const valid = "[limits]\ntimeout = 5m\nmemory = 2GiB\nload = 80%\n"
+	if err := s.Validate(strings.NewReader(valid)); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+	err := s.Validate(strings.NewReader("[limits]\nmemory = lots\n"))
+	if verr, ok := err.(*ini.ValidationError); !ok || verr.Desc != `invalid bytes value "lots"` {
+		t.Errorf("Validate: got %v, want invalid bytes value", err)
+	}
+}
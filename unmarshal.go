@@ -0,0 +1,324 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// Unmarshal parses the INI data from r and stores the values it contains in
+// the struct pointed to by v. Unmarshal panics if v is not a non-nil pointer
+// to a struct. It uses the default syntax; it is equivalent to
+// Dialect{}.Unmarshal(r, v).
+//
+// Sections and keys correspond to the fields of v as described by SchemaFor:
+// each field of struct type (or pointer to struct) holds a section, whose
+// keys are held by the fields of that struct, and other fields hold the keys
+// that occur before any section header. Field tags name the sections and
+// keys, and constrain their values:
+//
+//	type Config struct {
+//		Verbose bool `ini:"verbose"`
+//		Server  struct {
+//			Host  string   `ini:"host"`
+//			Port  int      `ini:"port" inimin:"1" inimax:"65535"`
+//			Alias []string `ini:"alias"`
+//		} `ini:"server"`
+//	}
+//
+// A key held by a []string field appends its values to the field each time
+// it occurs. A key held by any other field must have a single value, and a
+// later setting of the key replaces an earlier one. Sections and keys that
+// do not correspond to a field are ignored, and fields that do not
+// correspond to a key are unchanged, as are the "inidefault" tags.
+//
+// Integers are written in decimal, so that "010" is ten rather than eight,
+// and floats as accepted by strconv.ParseFloat; use an Unmarshaler to give
+// another NumberFormat, or decoders for keys. A value that cannot be stored
+// in its field, or that violates the constraints of its field, is reported
+// as a *ValidationError. Unmarshal panics before reading r if v has a field
+// that cannot hold a key, as SchemaFor does.
+func Unmarshal(r io.Reader, v interface{}) error { return Unmarshaler{}.Unmarshal(r, v) }
+
+// Unmarshal behaves as the Unmarshal function, but parses r using the syntax
+// of d. It is equivalent to Unmarshaler{Dialect: d}.Unmarshal(r, v).
+func (d Dialect) Unmarshal(r io.Reader, v interface{}) error {
+	return Unmarshaler{Dialect: d}.Unmarshal(r, v)
+}
+
+// An Unmarshaler stores INI data in tagged structs, as the Unmarshal
+// function does, with options. The zero value uses the default syntax.
+type Unmarshaler struct {
+	Dialect // the syntax of the input
+
+	// Numbers gives the format of int and float values, as Schema.Numbers
+	// does. The zero value accepts decimal integers and the floats of
+	// strconv.ParseFloat.
+	Numbers NumberFormat
+
+	// Decoders, if not nil, gives decoders for keys by pattern. A field whose
+	// key has a decoder holds the value returned by the decoder, which must
+	// be assignable to the field or to what it points to, or a number that
+	// converts exactly to a numeric field; a slice field appends the decoded
+	// value of each value of the key. Such a field may have any type,
+	// including a struct type such as time.Time, which otherwise holds a
+	// section, and its constraint tags are not used.
+	Decoders *DecoderRegistry
+}
+
+// Unmarshal parses the INI data from r and stores the values it contains in
+// the struct pointed to by v, as the Unmarshal function does.
+func (u Unmarshaler) Unmarshal(r io.Reader, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("ini: Unmarshal requires a pointer to a struct, not %T", v))
+	}
+	nf := u.Numbers
+	root := rv.Elem()
+
+	// Check every field before parsing, so that an unsupported field is
+	// reported the same way wherever it occurs.
+	sections := make(map[string]int) // section name → field index
+	keys := map[string]map[string]keyField{"": u.keyFields(root.Type(), "", true)}
+	for _, f := range schemaFields(root.Type()) {
+		if t := f.field.Type; u.isSection(f) {
+			if t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			sections[f.name] = f.field.Index[0]
+			keys[f.name] = u.keyFields(t, f.name, false)
+		}
+	}
+
+	// section returns the struct holding the named section, allocating it if
+	// necessary. It returns false if there is no such section.
+	section := func(name string) (reflect.Value, bool) {
+		if name == "" {
+			return root, true
+		}
+		i, ok := sections[name]
+		if !ok {
+			return reflect.Value{}, false
+		}
+		sv := root.Field(i)
+		if sv.Kind() == reflect.Pointer {
+			if sv.IsNil() {
+				sv.Set(reflect.New(sv.Type().Elem()))
+			}
+			sv = sv.Elem()
+		}
+		return sv, true
+	}
+	return Parse(r, Handler{
+		Dialect: u.Dialect,
+		Section: func(_ Location, name string) error {
+			section(name)
+			return nil
+		},
+		KeyValue: func(loc Location, key string, values []string) error {
+			if sv, ok := section(loc.Section); ok {
+				if f, ok := keys[loc.Section][key]; ok {
+					if f.dec != nil {
+						return storeDecoded(loc, key, sv.FieldByIndex(f.index), f.dec, values)
+					}
+					return storeValues(loc, sv.FieldByIndex(f.index), f.schema, values, nf)
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// A keyField is the struct field holding a key, and either the schema for the
+// key or the decoder registered for it.
+type keyField struct {
+	index  []int
+	schema *KeySchema
+	dec    Decoder
+}
+
+// keyFields returns the fields of struct type t that hold the keys of the
+// named section, by key name, skipping the fields that hold sections if top
+// is true. It panics if a field without a decoder cannot hold a key.
+func (u Unmarshaler) keyFields(t reflect.Type, section string, top bool) map[string]keyField {
+	fields := make(map[string]keyField)
+	for _, f := range schemaFields(t) {
+		if top && u.isSection(f) {
+			continue
+		} else if dec := u.decoder(section, f.name); dec != nil {
+			fields[f.name] = keyField{index: f.field.Index, dec: dec}
+		} else {
+			fields[f.name] = keyField{index: f.field.Index, schema: keySchemaFor(f)}
+		}
+	}
+	return fields
+}
+
+// decoder returns the decoder for the given key in section, or nil.
+func (u Unmarshaler) decoder(section, key string) Decoder {
+	if u.Decoders == nil {
+		return nil
+	}
+	return u.Decoders.Lookup(section, key)
+}
+
+// isSection reports whether the top-level field f holds a section: that is,
+// whether it is a struct or pointer to struct with no decoder for its key.
+func (u Unmarshaler) isSection(f schemaField) bool {
+	return isSection(f.field.Type) && u.decoder("", f.name) == nil
+}
+
+// isSection reports whether t is a struct or pointer to struct type.
+func isSection(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// storeValues checks values against ks, using nf to parse numbers, and stores
+// them in fv.
+func storeValues(loc Location, fv reflect.Value, ks *KeySchema, values []string,
+	nf NumberFormat) error {
+	if err := ks.check(loc, values, nf); err != nil {
+		return err
+	}
+	invalid := func(v string) error {
+		desc := fmt.Sprintf("invalid %v value %q", ks.typeLabel(), v)
+		return &ValidationError{Location: loc, Desc: desc, Key: ks.Name}
+	}
+	if ks.Type == TypeList {
+		for _, v := range values {
+			ev := reflect.New(fv.Type().Elem()).Elem()
+			ev.SetString(v) // the element may be a named string type
+			fv.Set(reflect.Append(fv, ev))
+		}
+		return nil
+	}
+	v := values[0]
+	var num float64
+	if ks.Unit != UnitNone {
+		num, _ = ks.Unit.Canonical(v) // already checked
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(v)
+	case reflect.Bool:
+		b, _ := ParseBool(v) // already checked
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := intValue(v, nf, ks.Unit, num)
+		if err != nil || fv.OverflowInt(n) {
+			return invalid(v)
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := intValue(v, nf, ks.Unit, num)
+		if err != nil || n < 0 || fv.OverflowUint(uint64(n)) {
+			return invalid(v)
+		}
+		fv.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		if ks.Unit == UnitNone {
+			num, _ = nf.ParseFloat(v) // already checked
+		}
+		if fv.OverflowFloat(num) {
+			return invalid(v)
+		}
+		fv.SetFloat(num)
+	}
+	return nil
+}
+
+// storeDecoded decodes values with dec, and stores the results in fv.
+func storeDecoded(loc Location, key string, fv reflect.Value, dec Decoder,
+	values []string) error {
+	fail := func(format string, args ...interface{}) error {
+		return &ValidationError{Location: loc, Desc: fmt.Sprintf(format, args...), Key: key}
+	}
+	vs := make([]interface{}, len(values))
+	for i, v := range values {
+		x, err := dec(v)
+		if err != nil {
+			return fail("invalid value %q: %v", v, err)
+		}
+		vs[i] = x
+	}
+	ft := fv.Type()
+	if len(vs) == 1 {
+		if xv, ok := convertTo(vs[0], ft); ok {
+			fv.Set(xv)
+			return nil
+		}
+	}
+	if ft.Kind() == reflect.Slice {
+		elems := make([]reflect.Value, len(vs))
+		for i, x := range vs {
+			xv, ok := convertTo(x, ft.Elem())
+			if !ok {
+				return fail("cannot store %T value in %v field", x, ft)
+			}
+			elems[i] = xv
+		}
+		fv.Set(reflect.Append(fv, elems...))
+		return nil
+	}
+	if len(vs) != 1 {
+		return fail(msgMultipleValues)
+	}
+	return fail("cannot store %T value in %v field", vs[0], ft)
+}
+
+// convertTo returns x as a value of type t, if x is assignable to t or is a
+// number whose value t can represent exactly, or as a pointer to such a value
+// if t is a pointer type.
+func convertTo(x interface{}, t reflect.Type) (reflect.Value, bool) {
+	xv := reflect.ValueOf(x)
+	if !xv.IsValid() {
+		return reflect.Value{}, false
+	} else if xv.Type().AssignableTo(t) {
+		return xv, true
+	} else if isNumber(xv.Kind()) && isNumber(t.Kind()) {
+		cv := xv.Convert(t)
+		if cv.Convert(xv.Type()).Interface() == x {
+			return cv, true
+		}
+	} else if t.Kind() == reflect.Pointer {
+		if ev, ok := convertTo(x, t.Elem()); ok {
+			p := reflect.New(t.Elem())
+			p.Elem().Set(ev)
+			return p, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// isNumber reports whether k is an integer or floating-point kind.
+func isNumber(k reflect.Kind) bool { return k >= reflect.Int && k <= reflect.Float64 }
+
+// intValue returns the integer value of v in format nf, or of num if unit is
+// not UnitNone.
+func intValue(v string, nf NumberFormat, unit Unit, num float64) (int64, error) {
+	if unit == UnitNone {
+		return nf.ParseInt(v)
+	}
+	if num != math.Trunc(num) || math.Abs(num) >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid integer %v", num)
+	}
+	return int64(num), nil
+}
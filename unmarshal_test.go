@@ -0,0 +1,205 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+type unmarshalConfig struct {
+	Verbose bool   `ini:"verbose"`
+	Name    string // untagged
+	Skip    string `ini:"-"`
+	Server  struct {
+		Host    string   `ini:"host"`
+		Port    uint16   `ini:"port" inimin:"1"`
+		Alias   []string `ini:"alias"`
+		Ratio   float32  `ini:"ratio"`
+		Timeout int      `ini:"timeout" iniunit:"seconds"`
+	} `ini:"server"`
+	Log   *unmarshalLog `ini:"log"`
+	Empty *unmarshalLog `ini:"empty"`
+	Unset *unmarshalLog `ini:"unset"`
+}
+
+type unmarshalLog struct {
+	Level string `ini:"level" inienum:"debug|info|error"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	const input = `verbose = yes
+Name = demo
+Skip = ignored
+other = ignored
+[server]
+host = example.com
+port = 8080
+alias = a
+alias = b
+ratio = 0.5
+timeout = 2m
+unknown = ignored
+[log]
+level = info
+[empty]
+[nonesuch]
+level = ignored
+`
+	cfg := unmarshalConfig{Skip: "kept"}
+	if err := ini.Unmarshal(strings.NewReader(input), &cfg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	want := unmarshalConfig{Verbose: true, Name: "demo", Skip: "kept"}
+	want.Server.Host = "example.com"
+	want.Server.Port = 8080
+	want.Server.Alias = []string{"a", "b"}
+	want.Server.Ratio = 0.5
+	want.Server.Timeout = 120
+	want.Log = &unmarshalLog{Level: "info"}
+	want.Empty = &unmarshalLog{}
+	if diff := cmp.Diff(want, cfg); diff != "" {
+		t.Errorf("Unmarshal (-want, +got):\n%s", diff)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		input, desc string
+	}{
+		{"verbose = maybe\n", `invalid bool value "maybe"`},
+		{"[server]\nport = 0\n", `value "0" is less than minimum 1`},
+		{"[server]\nport = 70000\n", `invalid int value "70000"`},
+		{"[server]\ntimeout = 1ms\n", `invalid seconds value "1ms"`},
+		{"[server]\nport = 0x50\n", `invalid int value "0x50"`},
+		{"[log]\nlevel = loud\n", `value "loud" is not one of ["debug" "info" "error"]`},
+	}
+	for _, test := range tests {
+		var cfg unmarshalConfig
+		err := ini.Unmarshal(strings.NewReader(test.input), &cfg)
+		if e, ok := err.(*ini.ValidationError); !ok || e.Desc != test.desc {
+			t.Errorf("Unmarshal(%q): got %v, want %s", test.input, err, test.desc)
+		}
+	}
+
+	// Multiple values for a single-valued key are rejected.
+	var cfg unmarshalConfig
+	err := ini.Dialect{ListValues: true}.Unmarshal(strings.NewReader("[server]\nhost = a, b\nalias = c, d\n"), &cfg)
+	if e, ok := err.(*ini.ValidationError); !ok || e.Key != "host" {
+		t.Errorf("Unmarshal list: got %v, want multiple values for host", err)
+	}
+
+	mustPanic := func(name string, v interface{}) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Unmarshal %s: did not panic", name)
+			}
+		}()
+		ini.Unmarshal(strings.NewReader(""), v)
+	}
+	mustPanic("non-pointer", cfg)
+	mustPanic("nil pointer", (*unmarshalConfig)(nil))
+	mustPanic("non-struct", new(int))
+
+	// Unsupported fields are reported before parsing, whether or not their
+	// section occurs in the input.
+	mustPanic("global field", &struct{ C chan int }{})
+	mustPanic("section field", &struct {
+		S struct{ C chan int }
+	}{})
+	mustPanic("section pointer field", &struct {
+		S *struct{ M map[string]string }
+	}{})
+}
+
+func TestUnmarshalNamedStrings(t *testing.T) {
+	type tag string
+	var cfg struct {
+		Tags []tag `ini:"tags"`
+		Main tag   `ini:"main"`
+	}
+	if err := ini.Unmarshal(strings.NewReader("tags = a\n  b\nmain = c\n"), &cfg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if diff := cmp.Diff([]tag{"a", "b"}, cfg.Tags); diff != "" || cfg.Main != "c" {
+		t.Errorf("Unmarshal: got %+v, want tags [a b] and main c", cfg)
+	}
+}
+
+func TestUnmarshalConform(t *testing.T) {
+	// Conform and Unmarshal read integers the same way.
+	type config struct {
+		Port int `ini:"port" inimin:"9" inimax:"10"`
+	}
+	tests := []struct {
+		input string
+		ok    bool
+		want  int
+	}{
+		{"port = 010\n", true, 10},
+		{"port = 0x1F\n", false, 0},
+		{"port = 1_000\n", false, 0},
+		{"port = 09\n", true, 9},
+		{"port = 08\n", false, 0}, // decimal 8, below the minimum
+	}
+	for _, test := range tests {
+		diags := ini.Conform(strings.NewReader(test.input), (*config)(nil))
+		var cfg config
+		err := ini.Unmarshal(strings.NewReader(test.input), &cfg)
+		if ok := len(diags) == 0; ok != test.ok {
+			t.Errorf("Conform(%q): got %v, want ok=%v", test.input, diags, test.ok)
+		}
+		if ok := err == nil; ok != test.ok {
+			t.Errorf("Unmarshal(%q): got %v, want ok=%v", test.input, err, test.ok)
+		} else if ok && cfg.Port != test.want {
+			t.Errorf("Unmarshal(%q): got port %d, want %d", test.input, cfg.Port, test.want)
+		}
+	}
+}
+
+func TestUnmarshalNumbers(t *testing.T) {
+	type config struct {
+		Count int     `ini:"count"`
+		Ratio float64 `ini:"ratio"`
+	}
+	tests := []struct {
+		u     ini.Unmarshaler
+		input string
+		want  config
+	}{
+		{ini.Unmarshaler{}, "count = 010\nratio = 1.5\n", config{Count: 10, Ratio: 1.5}},
+		{ini.Unmarshaler{}, "count = -7\nratio = 2e3\n", config{Count: -7, Ratio: 2000}},
+		{ini.Unmarshaler{Numbers: ini.CommaDecimal}, "count = 1.234\nratio = 0,25\n", config{Count: 1234, Ratio: 0.25}},
+		{ini.Unmarshaler{Numbers: ini.SpaceGrouped}, "count = 12 345\n", config{Count: 12345}},
+	}
+	for _, test := range tests {
+		var got config
+		if err := test.u.Unmarshal(strings.NewReader(test.input), &got); err != nil {
+			t.Errorf("Unmarshal(%q): unexpected error: %v", test.input, err)
+		} else if got != test.want {
+			t.Errorf("Unmarshal(%q): got %+v, want %+v", test.input, got, test.want)
+		}
+	}
+
+	var cfg config
+	err := ini.Unmarshaler{Numbers: ini.CommaDecimal}.Unmarshal(strings.NewReader("ratio = 1.5\n"), &cfg)
+	if e, ok := err.(*ini.ValidationError); !ok || e.Key != "ratio" {
+		t.Errorf("Unmarshal comma decimal: got %v, want invalid ratio", err)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A JoinStrategy selects how JoinValues combines the values of a key.
+type JoinStrategy int
+
+// Strategies for JoinValues.
+const (
+	JoinNewline JoinStrategy = iota // separate values with newlines
+	JoinSpace                       // separate values with single spaces
+	JoinComma                       // separate values with commas
+	JoinFirst                       // use only the first value
+	JoinLast                        // use only the last value
+)
+
+// JoinValues combines the values of a multi-valued key into a single string
+// according to strategy. If values is empty, the result is "". JoinValues
+// panics if strategy is not one of the Join constants.
+func JoinValues(values []string, strategy JoinStrategy) string {
+	if len(values) == 0 {
+		return ""
+	}
+	switch strategy {
+	case JoinNewline:
+		return strings.Join(values, "\n")
+	case JoinSpace:
+		return strings.Join(values, " ")
+	case JoinComma:
+		return strings.Join(values, ",")
+	case JoinFirst:
+		return values[0]
+	case JoinLast:
+		return values[len(values)-1]
+	}
+	panic(fmt.Sprintf("ini: invalid join strategy %d", strategy))
+}
+
+// A Normalizer rewrites a value before it is delivered; see
+// Dialect.Normalizers.
+type Normalizer func(value string) string
+
+// TrimQuotes is a Normalizer that removes a matching pair of single or
+// double quotes surrounding value. Other quotes are left unchanged.
+func TrimQuotes(value string) string {
+	if n := len(value); n >= 2 && (value[0] == '"' || value[0] == '\'') && value[n-1] == value[0] {
+		return value[1 : n-1]
+	}
+	return value
+}
+
+// DecodePercent is a Normalizer that replaces each escape sequence "%xx",
+// where xx is a pair of hexadecimal digits, with the byte it denotes. Other
+// uses of "%" are left unchanged.
+func DecodePercent(value string) string {
+	if !strings.Contains(value, "%") {
+		return value
+	}
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '%' && i+2 < len(value) {
+			if b, err := strconv.ParseUint(value[i+1:i+3], 16, 8); err == nil {
+				sb.WriteByte(byte(b))
+				i += 2
+				continue
+			}
+		}
+		sb.WriteByte(value[i])
+	}
+	return sb.String()
+}
+
+// UnescapeBackslashes is a Normalizer that replaces each backslash and the
+// character following it with that character, so that "\;" becomes ";" and
+// "\\" becomes "\". A backslash at the end of value is left unchanged.
+func UnescapeBackslashes(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+		}
+		sb.WriteByte(value[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJoinValues(t *testing.T) {
+	values := []string{"a", "b c", "d"}
+	tests := []struct {
+		values   []string
+		strategy ini.JoinStrategy
+		want     string
+	}{
+		{nil, ini.JoinNewline, ""},
+		{nil, ini.JoinLast, ""},
+		{[]string{"x"}, ini.JoinComma, "x"},
+		{values, ini.JoinNewline, "a\nb c\nd"},
+		{values, ini.JoinSpace, "a b c d"},
+		{values, ini.JoinComma, "a,b c,d"},
+		{values, ini.JoinFirst, "a"},
+		{values, ini.JoinLast, "d"},
+	}
+	for _, test := range tests {
+		if got := ini.JoinValues(test.values, test.strategy); got != test.want {
+			t.Errorf("JoinValues(%q, %d): got %q, want %q", test.values, test.strategy, got, test.want)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("JoinValues with invalid strategy did not panic")
+		}
+	}()
+	ini.JoinValues(values, ini.JoinStrategy(99))
+}
+
+func TestNormalizers(t *testing.T) {
+	tests := []struct {
+		name        string
+		norm        ini.Normalizer
+		input, want string
+	}{
+		{"TrimQuotes", ini.TrimQuotes, `"a b"`, "a b"},
+		{"TrimQuotes", ini.TrimQuotes, `'a b'`, "a b"},
+		{"TrimQuotes", ini.TrimQuotes, `"a b'`, `"a b'`},
+		{"TrimQuotes", ini.TrimQuotes, `"`, `"`},
+		{"DecodePercent", ini.DecodePercent, "a%20b%2fc", "a b/c"},
+		{"DecodePercent", ini.DecodePercent, "100% %zz %4", "100% %zz %4"},
+		{"UnescapeBackslashes", ini.UnescapeBackslashes, `a\;b\\c`, `a;b\c`},
+		{"UnescapeBackslashes", ini.UnescapeBackslashes, `end\`, `end\`},
+	}
+	for _, test := range tests {
+		if got := test.norm(test.input); got != test.want {
+			t.Errorf("%s(%q): got %q, want %q", test.name, test.input, got, test.want)
+		}
+	}
+
+	var got []ini.Entry
+	h := ini.CollectOrdered(&got)
+	h.Dialect = ini.Dialect{
+		ListValues:  true,
+		Normalizers: []ini.Normalizer{ini.TrimQuotes, ini.DecodePercent},
+	}
+	if err := ini.Parse(strings.NewReader("k = \"a%20b\", 'c'\n  \"%41\"\n"), h); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"a b", "c", "A"}, got[0].Values); diff != "" {
+		t.Errorf("Normalized values (-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import "strings"
+
+// ExpandVars returns a Handler that replaces references of the form $(name)
+// in values with the value of the variable name, and delivers the result to
+// h. The sequence "$$" denotes a single "$". Comments, section headers, and
+// the options of h are passed through unchanged.
+//
+// Variables are defined by the keys of the section named section, which may
+// occur more than once. A definition takes effect for the values that follow
+// it, and may refer to variables defined before it. A variable may be
+// redefined, and a redefinition may refer to the variable's previous value,
+// so definitions are expanded in order and cannot form a cycle. The values of
+// a multi-valued definition are joined with spaces. For example:
+//
+//	[vars]
+//	prefix = /usr/local
+//	bindir = $(prefix)/bin
+//	[install]
+//	path = $(bindir):/usr/bin
+//
+// delivers "path" with the value "/usr/local/bin:/usr/bin". The keys of the
+// variable section are also delivered to h, with their values expanded.
+//
+// A reference to an undefined variable, or a "$(" without a matching ")", is
+// reported as a *SyntaxError.
+func ExpandVars(section string, h Handler) Handler {
+	vars := make(map[string]string)
+	kv := h.KeyValue
+	h.KeyValue = func(loc Location, key string, values []string) error {
+		out := make([]string, len(values))
+		for i, v := range values {
+			x, err := expandVars(loc, v, vars)
+			if err != nil {
+				return err
+			}
+			out[i] = x
+		}
+		if loc.Section == section {
+			vars[key] = JoinValues(out, JoinSpace)
+		}
+		if kv == nil {
+			return nil
+		}
+		return kv(loc, key, out)
+	}
+	return h
+}
+
+// expandVars returns a copy of s with references to vars expanded.
+func expandVars(loc Location, s string, vars map[string]string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+	var sb strings.Builder
+	for {
+		i := strings.Index(s, "$")
+		if i < 0 || i+1 == len(s) {
+			sb.WriteString(s)
+			return sb.String(), nil
+		}
+		sb.WriteString(s[:i])
+		switch s[i+1] {
+		case '$':
+			sb.WriteByte('$')
+			s = s[i+2:]
+		case '(':
+			j := strings.Index(s[i+2:], ")")
+			if j < 0 {
+				return "", &SyntaxError{Location: loc, Desc: "unterminated variable reference", Key: s[i:]}
+			}
+			name := s[i+2 : i+2+j]
+			v, ok := vars[name]
+			if !ok {
+				return "", &SyntaxError{Location: loc, Desc: "undefined variable", Key: name}
+			}
+			sb.WriteString(v)
+			s = s[i+3+j:]
+		default:
+			sb.WriteByte('$')
+			s = s[i+1:]
+		}
+	}
+}
+
+// A VarLookup returns the value of the variable with the given name, and
+// reports whether the variable is defined.
+type VarLookup func(name string) (string, bool)
+
+// VarMap returns a VarLookup that looks up variables in m.
+func VarMap(m map[string]string) VarLookup {
+	return func(name string) (string, bool) { v, ok := m[name]; return v, ok }
+}
+
+// ExpandNames returns a Handler that replaces tokens of the form $NAME in
+// values with the value reported by lookup for NAME, and delivers the result
+// to h. A name begins with a letter or underscore, followed by letters,
+// digits, and underscores. The sequence "$$" denotes a single "$", and a "$"
+// not followed by a name is left unchanged. Comments, section headers, and
+// the options of h are passed through unchanged. For example, with ROOT
+// defined as "/src":
+//
+//	parent = $ROOT/lib
+//
+// delivers "parent" with the value "/src/lib".
+//
+// If strict is true, a reference to a name undefined by lookup is reported as
+// a *SyntaxError. Otherwise, the reference is left unchanged.
+func ExpandNames(lookup VarLookup, strict bool, h Handler) Handler {
+	kv := h.KeyValue
+	h.KeyValue = func(loc Location, key string, values []string) error {
+		out := make([]string, len(values))
+		for i, v := range values {
+			x, err := expandNames(loc, v, lookup, strict)
+			if err != nil {
+				return err
+			}
+			out[i] = x
+		}
+		if kv == nil {
+			return nil
+		}
+		return kv(loc, key, out)
+	}
+	return h
+}
+
+// expandNames returns a copy of s with $NAME references expanded by lookup.
+func expandNames(loc Location, s string, lookup VarLookup, strict bool) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+	var sb strings.Builder
+	for {
+		i := strings.Index(s, "$")
+		if i < 0 || i+1 == len(s) {
+			sb.WriteString(s)
+			return sb.String(), nil
+		}
+		sb.WriteString(s[:i])
+		if s[i+1] == '$' {
+			sb.WriteByte('$')
+			s = s[i+2:]
+			continue
+		}
+		j := i + 1
+		for j < len(s) && isNameByte(s[j], j == i+1) {
+			j++
+		}
+		name := s[i+1 : j]
+		if name == "" {
+			sb.WriteByte('$')
+		} else if v, ok := lookup(name); ok {
+			sb.WriteString(v)
+		} else if strict {
+			return "", &SyntaxError{Location: loc, Desc: "undefined variable", Key: name}
+		} else {
+			sb.WriteString(s[i:j])
+		}
+		s = s[j:]
+	}
+}
+
+// isNameByte reports whether c may occur in a $NAME reference, at the start
+// of the name if first is true.
+func isNameByte(c byte, first bool) bool {
+	switch {
+	case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		return true
+	case '0' <= c && c <= '9':
+		return !first
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExpandVars(t *testing.T) {
+	const input = `[vars]
+prefix = /usr/local
+bindir = $(prefix)/bin
+[install]
+path = $(bindir):/usr/bin
+cost = $$5 or $5
+[vars]
+prefix = $(prefix)/opt
+flags = -O2
+  -g
+[build]
+cmd = cc $(flags) --prefix=$(prefix)
+`
+	m := make(map[string]map[string][]string)
+	if err := ini.Parse(strings.NewReader(input), ini.ExpandVars("vars", ini.CollectMap(m))); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := map[string]map[string][]string{
+		"vars": {
+			"prefix": {"/usr/local", "/usr/local/opt"},
+			"bindir": {"/usr/local/bin"},
+			"flags":  {"-O2", "-g"},
+		},
+		"install": {"path": {"/usr/local/bin:/usr/bin"}, "cost": {"$5 or $5"}},
+		"build":   {"cmd": {"cc -O2 -g --prefix=/usr/local/opt"}},
+	}
+	if diff := cmp.Diff(want, m); diff != "" {
+		t.Errorf("ExpandVars (-want, +got)\n%s", diff)
+	}
+
+	tests := []struct {
+		input, desc, key string
+	}{
+		{"[vars]\na = $(a)\n", "undefined variable", "a"},
+		{"x = $(later)\n[vars]\nlater = 1\n", "undefined variable", "later"},
+		{"x = $(open\n", "unterminated variable reference", "$(open"},
+	}
+	for _, test := range tests {
+		err := ini.Parse(strings.NewReader(test.input), ini.ExpandVars("vars", ini.Handler{}))
+		if e, ok := err.(*ini.SyntaxError); !ok || e.Desc != test.desc || e.Key != test.key {
+			t.Errorf("Parse(%q): got %v, want %s: %s", test.input, err, test.desc, test.key)
+		}
+	}
+}
+
+func TestExpandNames(t *testing.T) {
+	const input = `[component_0]
+type = Group
+parent = $ROOT
+path = $ROOT/lib/$SUB_DIR2x
+cost = $$5 $ $1 $UNKNOWN
+`
+	vars := ini.VarMap(map[string]string{"ROOT": "/src", "SUB_DIR2x": "ir"})
+	m := make(map[string]map[string][]string)
+	if err := ini.Parse(strings.NewReader(input), ini.ExpandNames(vars, false, ini.CollectMap(m))); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := map[string]map[string][]string{
+		"component_0": {
+			"type":   {"Group"},
+			"parent": {"/src"},
+			"path":   {"/src/lib/ir"},
+			"cost":   {"$5 $ $1 $UNKNOWN"},
+		},
+	}
+	if diff := cmp.Diff(want, m); diff != "" {
+		t.Errorf("ExpandNames (-want, +got)\n%s", diff)
+	}
+
+	env := func(name string) (string, bool) {
+		if name == "HOME" {
+			return "/home/me", true
+		}
+		return "", false
+	}
+	err := ini.Parse(strings.NewReader(input), ini.ExpandNames(env, true, ini.Handler{}))
+	if e, ok := err.(*ini.SyntaxError); !ok || e.Desc != "undefined variable" || e.Key != "ROOT" {
+		t.Errorf("Parse strict: got %v, want undefined variable: ROOT", err)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini
+
+import "strings"
+
+// A TestVector is an input for Parse with the results it should produce.
+type TestVector struct {
+	Name   string  // a short description of the case
+	Input  string  // the input text
+	Events []Event // the expected events, as delivered by EventHandler
+	Err    string  // if not empty, the expected error text
+}
+
+// TestVectors returns a collection of inputs covering edge cases of the
+// default syntax, with the results Parse produces for them, so that other
+// implementations and dialects can check their conformance. When Err is set,
+// Events holds the events delivered before the error. Each call returns a
+// fresh copy that the caller may modify.
+func TestVectors() []TestVector {
+	long := strings.Repeat("x", 60000)
+	return []TestVector{
+		{Name: "empty input"},
+		{Name: "blank lines only", Input: "\n  \n\t\n"},
+		{Name: "tab continuation", Input: "k = a\n\tb\n", Events: []Event{
+			{Kind: KindKey, Line: 1, Name: "k", Values: []string{"a", "b"}},
+		}},
+		{Name: "empty first value", Input: "k =\n  a\n  b\n", Events: []Event{
+			{Kind: KindKey, Line: 1, Name: "k", Values: []string{"a", "b"}},
+		}},
+		{Name: "CRLF line endings", Input: "[s]\r\nk = v\r\n  w\r\n", Events: []Event{
+			{Kind: KindSection, Line: 1, Name: "s"},
+			{Kind: KindKey, Line: 2, Section: "s", Name: "k", Values: []string{"v", "w"}},
+		}},
+		{Name: "no final newline", Input: "k = v", Events: []Event{
+			{Kind: KindKey, Line: 1, Name: "k", Values: []string{"v"}},
+		}},
+		{Name: "lone open bracket", Input: "[", Err: "line 1: unclosed section header"},
+		{Name: "lone close bracket", Input: "]", Events: []Event{
+			{Kind: KindKey, Line: 1, Name: "]", Values: []string{""}},
+		}},
+		{Name: "brackets in value", Input: "k = [v]\n", Events: []Event{
+			{Kind: KindKey, Line: 1, Name: "k", Values: []string{"[v]"}},
+		}},
+		{Name: "equals in value", Input: "k = a=b\n", Events: []Event{
+			{Kind: KindKey, Line: 1, Name: "k", Values: []string{"a=b"}},
+		}},
+		{Name: "empty key", Input: "k = 1\n = 2\n", Err: "line 2: empty key"},
+		{Name: "spaces in names", Input: "[ a \t b ]\n  x   y = 1\n", Events: []Event{
+			{Kind: KindSection, Line: 1, Name: "a b"},
+			{Kind: KindKey, Line: 2, Section: "a b", Name: "x y", Values: []string{"1"}},
+		}},
+		{Name: "ideographic space in key", Input: "a\u3000b = 1\n", Events: []Event{
+			{Kind: KindKey, Line: 1, Name: "a b", Values: []string{"1"}},
+		}},
+		{Name: "no-break space indentation", Input: "k = a\n\u00a0b\n", Events: []Event{
+			{Kind: KindKey, Line: 1, Name: "k", Values: []string{"a"}},
+			{Kind: KindKey, Line: 2, Name: "b", Values: []string{""}},
+		}},
+		{Name: "comment ends values", Input: "k = a\n; c\n  b\n", Events: []Event{
+			{Kind: KindKey, Line: 1, Name: "k", Values: []string{"a"}},
+			{Kind: KindComment, Line: 2, Text: "; c"},
+			{Kind: KindKey, Line: 3, Name: "b", Values: []string{""}},
+		}},
+		{Name: "repeated key", Input: "k = 1\nk = 2\nj = 3\nk = 4\n", Events: []Event{
+			{Kind: KindKey, Line: 1, Name: "k", Values: []string{"1", "2"}},
+			{Kind: KindKey, Line: 3, Name: "j", Values: []string{"3"}},
+			{Kind: KindKey, Line: 4, Name: "k", Values: []string{"4"}},
+		}},
+		{Name: "long line", Input: "k = " + long + "\n", Events: []Event{
+			{Kind: KindKey, Line: 1, Name: "k", Values: []string{long}},
+		}},
+		{Name: "line too long", Input: "k = " + long + long + "\n", Err: "line 1: read error: bufio.Scanner: token too long"},
+	}
+}
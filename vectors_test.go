@@ -0,0 +1,40 @@
+// Copyright 2026 Michael J. Fromberger. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ini_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/creachadair/ini"
+	"github.com/creachadair/ini/initest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTestVectors(t *testing.T) {
+	for _, v := range ini.TestVectors() {
+		t.Run(v.Name, func(t *testing.T) {
+			got, err := initest.Events(strings.NewReader(v.Input), ini.Parse)
+			if v.Err == "" && err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			} else if v.Err != "" && (err == nil || err.Error() != v.Err) {
+				t.Fatalf("Parse: got error %v, want %q", err, v.Err)
+			}
+			if diff := cmp.Diff(v.Events, got); diff != "" {
+				t.Errorf("Events (-want, +got)\n%s", diff)
+			}
+		})
+	}
+}